@@ -0,0 +1,309 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"strings"
+)
+
+var slugPattern = regexp.MustCompile(`^[a-z0-9-]+$`)
+
+// Board configures one private leaderboard.
+type Board struct {
+	Id int64 `json:"id"`
+	Name string `json:"name"`
+	SessionCookie string `json:"session_cookie"`
+	Source string `json:"source"`
+	SourceFile string `json:"source_file"`
+	// BaseUrl is where the aoc source fetches from instead of
+	// adventofcode.com, e.g. a mock-aoc server.
+	BaseUrl string `json:"base_url"`
+	// PollInterval is the number of seconds between updates during the
+	// event. Leaderboards on AoC are never polled more often than every
+	// 15 minutes.
+	PollInterval int64 `json:"poll_interval"`
+	// OffSeasonInterval is the number of seconds between updates outside
+	// December 1st to 25th.
+	OffSeasonInterval int64 `json:"off_season_interval"`
+	// Jitter is the maximum number of seconds added to each interval.
+	Jitter int64 `json:"jitter"`
+	// Scoring names the strategy ranking the totals, e.g. "points".
+	Scoring string `json:"scoring"`
+	// PartialDays decides how days with only part 1 done count in the
+	// totals: "complete", "stars" or "penalty".
+	PartialDays string `json:"partial_days"`
+	// PartialPenalty is the number of seconds a missing part 2 takes with
+	// the "penalty" policy.
+	PartialPenalty int64 `json:"partial_penalty"`
+	// ExcludedDays are the days of each year left out of the scoring on
+	// this board, besides those excluded on all boards.
+	ExcludedDays map[int64][]int `json:"excluded_days"`
+}
+
+// Merged configures a leaderboard combining the members of several
+// private leaderboards.
+type Merged struct {
+	// Slug names the merged leaderboard in its URL, /merged/{slug}.
+	Slug string `json:"slug"`
+	Name string `json:"name"`
+	// Boards are the ids of the leaderboards merged.
+	Boards []int64 `json:"boards"`
+	Scoring string `json:"scoring"`
+	PartialDays string `json:"partial_days"`
+	PartialPenalty int64 `json:"partial_penalty"`
+	ExcludedDays map[int64][]int `json:"excluded_days"`
+}
+
+// Start registers when a member starts the puzzles, on every leaderboard
+// they are on.
+type Start struct {
+	MemberId int `json:"member_id"`
+	// Time is the time of day the member starts, e.g. "07:00", in
+	// Timezone, a name like "America/Los_Angeles" or an offset like
+	// "-08:00".
+	Timezone string `json:"timezone"`
+	Time string `json:"time"`
+	// Days are the starts of single days, by day number, as RFC 3339
+	// times. They override Time.
+	Days map[int]string `json:"days"`
+}
+
+// Team is a group of members competing together, on every leaderboard
+// they are on.
+type Team struct {
+	Name string `json:"name"`
+	Members []int `json:"members"`
+}
+
+type Config struct {
+	// Year is the current event. Earlier years back to FirstYear are
+	// served as an archive.
+	Year int64 `json:"year"`
+	FirstYear int64 `json:"first_year"`
+	// DataDir is where fetched leaderboards are stored. Storing is
+	// disabled when it is empty.
+	DataDir string `json:"data_dir"`
+	DataKeep int `json:"data_keep"`
+	DataMaxAgeDays int64 `json:"data_max_age_days"`
+	// RequestSpacing is the minimum number of seconds between two requests
+	// to AoC, shared by all leaderboards.
+	RequestSpacing int64 `json:"request_spacing"`
+	// AlertWebhook is posted to when a session cookie is rejected or a
+	// leaderboard has been stale for StaleAlertAfter seconds.
+	AlertWebhook string `json:"alert_webhook"`
+	StaleAlertAfter int64 `json:"stale_alert_after"`
+	Boards []Board `json:"boards"`
+	Merged []Merged `json:"merged"`
+	Starts []Start `json:"starts"`
+	// ExcludedDays are the days of each year left out of the scoring on
+	// all boards, e.g. {"2018": [6]} for the day AoC voided.
+	ExcludedDays map[int64][]int `json:"excluded_days"`
+	Teams []Team `json:"teams"`
+	// TeamScoring decides how the members make up the score of a team:
+	// "best" for the points of the best TeamBest members, "average" or
+	// "stars".
+	TeamScoring string `json:"team_scoring"`
+	TeamBest int `json:"team_best"`
+}
+
+// Load reads a JSON configuration file.
+func Load(path string) (*Config, error) {
+	body, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	c := Config{}
+	if err := json.Unmarshal(body, &c); err != nil {
+		return nil, fmt.Errorf("parsing %s: %v", path, err)
+	}
+
+	for _, b := range c.Boards {
+		if b.Id == 0 {
+			return nil, fmt.Errorf("parsing %s: board without id", path)
+		}
+	}
+
+	for _, m := range c.Merged {
+		if !slugPattern.MatchString(m.Slug) {
+			return nil, fmt.Errorf("parsing %s: merged board slug %q must be lower case letters, digits and dashes", path, m.Slug)
+		}
+		if len(m.Boards) == 0 {
+			return nil, fmt.Errorf("parsing %s: merged board %s includes no boards", path, m.Slug)
+		}
+		for _, id := range m.Boards {
+			if !c.hasBoard(id) {
+				return nil, fmt.Errorf("parsing %s: merged board %s includes unknown board %d", path, m.Slug, id)
+			}
+		}
+	}
+
+	for year, days := range c.ExcludedDays {
+		if err := checkDays(days); err != nil {
+			return nil, fmt.Errorf("parsing %s: excluded days of %d: %v", path, year, err)
+		}
+	}
+	for _, b := range c.Boards {
+		for year, days := range b.ExcludedDays {
+			if err := checkDays(days); err != nil {
+				return nil, fmt.Errorf("parsing %s: excluded days of board %d in %d: %v", path, b.Id, year, err)
+			}
+		}
+	}
+	for _, m := range c.Merged {
+		for year, days := range m.ExcludedDays {
+			if err := checkDays(days); err != nil {
+				return nil, fmt.Errorf("parsing %s: excluded days of merged board %s in %d: %v", path, m.Slug, year, err)
+			}
+		}
+	}
+
+	teams := make(map[string]bool)
+	for _, t := range c.Teams {
+		if t.Name == "" {
+			return nil, fmt.Errorf("parsing %s: team without name", path)
+		}
+		// The name is part of the URL of the team's page.
+		if strings.ContainsAny(t.Name, "/?#") {
+			return nil, fmt.Errorf("parsing %s: team name %q can't contain /, ? or #", path, t.Name)
+		}
+		if teams[t.Name] {
+			return nil, fmt.Errorf("parsing %s: more than one team named %q", path, t.Name)
+		}
+		teams[t.Name] = true
+	}
+
+	for _, s := range c.Starts {
+		if s.MemberId == 0 {
+			return nil, fmt.Errorf("parsing %s: start without member_id", path)
+		}
+	}
+
+	return &c, nil
+}
+
+func checkDays(days []int) error {
+	for _, day := range days {
+		if day < 1 || day > 25 {
+			return fmt.Errorf("day %d is not between 1 and 25", day)
+		}
+	}
+	return nil
+}
+
+// Excluded returns the days of year left out of the scoring on all boards
+// and in days.
+func (c *Config) Excluded(year int64, days map[int64][]int) map[int]bool {
+	excluded := make(map[int]bool)
+	for _, day := range c.ExcludedDays[year] {
+		excluded[day] = true
+	}
+	for _, day := range days[year] {
+		excluded[day] = true
+	}
+	return excluded
+}
+
+func (c *Config) hasBoard(id int64) bool {
+	for _, b := range c.Boards {
+		if b.Id == id {
+			return true
+		}
+	}
+	return false
+}
+
+// Defaults fills in the fields left empty in the config file from
+// defaults, and the fields left empty for each board from board.
+func (c *Config) Defaults(defaults Config, board Board) {
+	if c.Year == 0 {
+		c.Year = defaults.Year
+	}
+	if c.FirstYear == 0 {
+		c.FirstYear = defaults.FirstYear
+	}
+	if c.FirstYear == 0 || c.FirstYear > c.Year {
+		c.FirstYear = c.Year
+	}
+	if c.DataDir == "" {
+		c.DataDir = defaults.DataDir
+	}
+	if c.DataKeep == 0 {
+		c.DataKeep = defaults.DataKeep
+	}
+	if c.DataMaxAgeDays == 0 {
+		c.DataMaxAgeDays = defaults.DataMaxAgeDays
+	}
+	if c.RequestSpacing == 0 {
+		c.RequestSpacing = defaults.RequestSpacing
+	}
+	if c.AlertWebhook == "" {
+		c.AlertWebhook = defaults.AlertWebhook
+	}
+	if c.StaleAlertAfter == 0 {
+		c.StaleAlertAfter = defaults.StaleAlertAfter
+	}
+	if c.ExcludedDays == nil {
+		c.ExcludedDays = defaults.ExcludedDays
+	}
+	if c.TeamScoring == "" {
+		c.TeamScoring = defaults.TeamScoring
+	}
+	if c.TeamBest == 0 {
+		c.TeamBest = defaults.TeamBest
+	}
+
+	for i := range c.Boards {
+		b := &c.Boards[i]
+		if b.Name == "" {
+			b.Name = fmt.Sprintf("%d", b.Id)
+		}
+		if b.SessionCookie == "" {
+			b.SessionCookie = board.SessionCookie
+		}
+		if b.Source == "" {
+			b.Source = board.Source
+		}
+		if b.SourceFile == "" {
+			b.SourceFile = board.SourceFile
+		}
+		if b.BaseUrl == "" {
+			b.BaseUrl = board.BaseUrl
+		}
+		if b.PollInterval == 0 {
+			b.PollInterval = board.PollInterval
+		}
+		if b.OffSeasonInterval == 0 {
+			b.OffSeasonInterval = board.OffSeasonInterval
+		}
+		if b.Jitter == 0 {
+			b.Jitter = board.Jitter
+		}
+		if b.Scoring == "" {
+			b.Scoring = board.Scoring
+		}
+		if b.PartialDays == "" {
+			b.PartialDays = board.PartialDays
+		}
+		if b.PartialPenalty == 0 {
+			b.PartialPenalty = board.PartialPenalty
+		}
+	}
+
+	for i := range c.Merged {
+		if c.Merged[i].Name == "" {
+			c.Merged[i].Name = c.Merged[i].Slug
+		}
+		if c.Merged[i].Scoring == "" {
+			c.Merged[i].Scoring = board.Scoring
+		}
+		if c.Merged[i].PartialDays == "" {
+			c.Merged[i].PartialDays = board.PartialDays
+		}
+		if c.Merged[i].PartialPenalty == 0 {
+			c.Merged[i].PartialPenalty = board.PartialPenalty
+		}
+	}
+}
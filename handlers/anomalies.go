@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"fmt"
+	"github.com/bradfitz/iter"
+	"github.com/tlj/aoc-leaderboard-go/leaderboard"
+	"html/template"
+	"log"
+	"net/http"
+	"time"
+)
+
+type anomaly struct {
+	leaderboard.Anomaly
+	At time.Time
+}
+
+type boardAnomalies struct {
+	Name string
+	Year int64
+	Url string
+	Anomalies []anomaly
+}
+
+// Anomalies lists the stars with impossible timestamps on every
+// leaderboard.
+func Anomalies(w http.ResponseWriter, r *http.Request) {
+	var boards []boardAnomalies
+	for _, board := range leaderboard.Boards.AllYears() {
+		snapshot := board.Snapshot()
+		if len(snapshot.Anomalies) == 0 {
+			continue
+		}
+
+		b := boardAnomalies{
+			Name: board.Name,
+			Year: board.Year,
+			Url: fmt.Sprintf("%s/year/%d", board.Url(), board.Year),
+		}
+		for _, a := range snapshot.Anomalies {
+			b.Anomalies = append(b.Anomalies, anomaly{a, time.Unix(a.Ts, 0).UTC()})
+		}
+		boards = append(boards, b)
+	}
+
+	funcMap := template.FuncMap{
+		"readableTime": leaderboard.ReadableTime,
+		"N": iter.N,
+	}
+
+	type Context map[string]interface{}
+	c := Context{
+		"boards": boards,
+	}
+
+	tmpl := template.Must(template.New("anomalies.html").Funcs(funcMap).ParseGlob("templates/*.html"))
+	err := tmpl.ExecuteTemplate(w, "anomalies.html", c)
+	if err != nil {
+		log.Printf("Error executin template: %v", err)
+	}
+}
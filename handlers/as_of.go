@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"fmt"
+	"github.com/tlj/aoc-leaderboard-go/leaderboard"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+var asOfLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04Z07:00",
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02",
+}
+
+// snapshotFromRequest returns the snapshot of board to render. With an
+// asOf query parameter, e.g. ?asOf=2018-12-07T12:00Z, the scores are
+// calculated from the stars earned before that moment only. query holds
+// the query string to keep in links to other pages.
+func snapshotFromRequest(r *http.Request, board *leaderboard.LeaderBoard) (snapshot *leaderboard.Snapshot, asOf time.Time, query string, err error) {
+	value := r.URL.Query().Get("asOf")
+	if value == "" {
+		return board.Snapshot(), time.Time{}, "", nil
+	}
+
+	for _, layout := range asOfLayouts {
+		asOf, err = time.Parse(layout, value)
+		if err == nil {
+			query = "?asOf=" + url.QueryEscape(asOf.UTC().Format(time.RFC3339))
+			return board.SnapshotAt(asOf), asOf.UTC(), query, nil
+		}
+	}
+
+	return nil, time.Time{}, "", fmt.Errorf("invalid asOf %q", value)
+}
@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"fmt"
+	"github.com/bradfitz/iter"
+	"github.com/gorilla/mux"
+	"github.com/tlj/aoc-leaderboard-go/leaderboard"
+	"html/template"
+	"log"
+	"net/http"
+	"strconv"
+)
+
+// boardFromRequest returns the leaderboard selected by the {id} or {merged}
+// and {year} route variables, falling back to the default board and its
+// latest year. boardUrl is the URL prefix of the board's pages, and baseUrl
+// the prefix of the pages of the selected year.
+func boardFromRequest(r *http.Request) (board *leaderboard.LeaderBoard, boardUrl string, baseUrl string, ok bool) {
+	vars := mux.Vars(r)
+
+	var key string
+	if idVar, hasId := vars["id"]; hasId {
+		id, err := strconv.ParseInt(idVar, 10, 64)
+		if err != nil {
+			return nil, "", "", false
+		}
+		key = strconv.FormatInt(id, 10)
+		boardUrl = fmt.Sprintf("/board/%d", id)
+	} else if slug, isMerged := vars["merged"]; isMerged {
+		key = "merged/" + slug
+		boardUrl = "/merged/" + slug
+	} else {
+		board = leaderboard.Boards.Default()
+		if board == nil {
+			return nil, "", "", false
+		}
+		key = board.Key()
+	}
+
+	yearVar, hasYear := vars["year"]
+	if !hasYear {
+		board, ok = leaderboard.Boards.Latest(key)
+		return board, boardUrl, boardUrl, ok
+	}
+
+	year, err := strconv.ParseInt(yearVar, 10, 64)
+	if err != nil {
+		return nil, "", "", false
+	}
+
+	board, ok = leaderboard.Boards.Get(key, year)
+	return board, boardUrl, fmt.Sprintf("%s/year/%d", boardUrl, year), ok
+}
+
+// menuContext returns the template context the menu and banners of the
+// pages of board need.
+func menuContext(board *leaderboard.LeaderBoard, boardUrl string, baseUrl string) map[string]interface{} {
+	return map[string]interface{}{
+		"year": board.Year,
+		"board": board.Status(),
+		"boardName": board.Name,
+		"boardCount": leaderboard.Boards.Len(),
+		"boardUrl": boardUrl,
+		"baseUrl": baseUrl,
+		"years": leaderboard.Boards.Years(board.Key()),
+		"excludedDays": board.ExcludedDays,
+		"hasTeams": len(board.Teams) > 0,
+	}
+}
+
+func Boards(w http.ResponseWriter, r *http.Request) {
+	type Context map[string]interface{}
+	c := Context{
+		"boards": leaderboard.Boards.All(),
+	}
+
+	funcMap := template.FuncMap{
+		"readableTime": leaderboard.ReadableTime,
+		"N": iter.N,
+	}
+
+	tmpl := template.Must(template.New("boards.html").Funcs(funcMap).ParseGlob("templates/*.html"))
+	err := tmpl.ExecuteTemplate(w, "boards.html", c)
+	if err != nil {
+		log.Printf("Error executin template: %v", err)
+	}
+}
@@ -8,19 +8,28 @@ import (
 	"html/template"
 	"log"
 	"net/http"
-	"sort"
 	"strconv"
 )
 
 func Day(w http.ResponseWriter, r *http.Request) {
+	board, boardUrl, baseUrl, ok := boardFromRequest(r)
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	snapshot, asOf, query, err := snapshotFromRequest(r, board)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
 	vars := mux.Vars(r)
 
 	var day int64
-	var err error
 
-	_, ok := vars["day"]
+	_, ok = vars["day"]
 	if !ok {
-		day = leaderboard.CurrentBoard.MaxDay
+		day = snapshot.MaxDay
 	} else {
 		day, err = strconv.ParseInt(vars["day"], 10, 64)
 		if err != nil {
@@ -31,35 +40,33 @@ func Day(w http.ResponseWriter, r *http.Request) {
 
 	var memberScores []*member_score.MemberScore
 	if day == 0 {
-		for _, memberScore := range leaderboard.CurrentBoard.Totals {
+		for _, memberScore := range snapshot.Totals {
 			memberScores = append(memberScores, memberScore)
 		}
-	} else {
-		for _, memberScore := range leaderboard.CurrentBoard.Days[int(day)].MemberScores {
+	} else if d, ok := snapshot.Days[int(day)]; ok {
+		for _, memberScore := range d.MemberScores {
 			memberScores = append(memberScores, memberScore)
 		}
 	}
 
-	orderBy, ok := vars["orderBy"]
-	if !ok {
-		orderBy = "part2diff"
+	// The board's strategy ranks totals, which a single day may not have,
+	// e.g. ratings or streaks.
+	fallback := board.Scoring
+	if day > 0 {
+		fallback = member_score.DefaultStrategy
 	}
+	strategy := sortMemberScores(memberScores, vars["orderBy"], fallback)
+	orderBy := strategy.Name()
 
-	if orderBy == "part2diff" {
-		sort.Sort(member_score.ByPart2Diff(memberScores))
-	} else if orderBy == "part1" {
-		sort.Sort(member_score.ByPart1(memberScores))
-	} else if orderBy == "part2" {
-		sort.Sort(member_score.ByPart2(memberScores))
-	} else if orderBy == "ogscore" {
-		sort.Sort(member_score.ByAocGlobalScore(memberScores))
-	} else if orderBy == "olscore" {
-		sort.Sort(member_score.ByAocLocalScore(memberScores))
-	} else if orderBy == "name" {
-		sort.Sort(member_score.ByName(memberScores))
-	} else {
-		sort.Sort(member_score.ByPart2Diff(memberScores))
-		orderBy = "part2diff"
+	stat := member_score.StatAverage
+	if day == 0 {
+		stat = member_score.StatOf(orderBy)
+	}
+	part1OrderBy, part2OrderBy := member_score.StatOrderBy(stat)
+
+	topScores := snapshot.TopScores
+	if len(topScores) > 20 {
+		topScores = topScores[:20]
 	}
 
 	type DayScores map[string]interface{}
@@ -67,15 +74,31 @@ func Day(w http.ResponseWriter, r *http.Request) {
 	type Context map[string]interface{}
 	c := Context{
 		"day": day,
-		"year": leaderboard.CurrentBoard.Year,
 		"orderBy": orderBy,
 		"dayScores": DayScores{
 			"day": day,
 			"scores": memberScores,
 			"orderBy": orderBy,
+			"baseUrl": baseUrl,
+			"query": query,
+			"columns": columnSet(board, strategy),
+			"stat": stat,
+			"part1OrderBy": part1OrderBy,
+			"part2OrderBy": part2OrderBy,
 		},
-		"topScores": leaderboard.CurrentBoard.TopScores[:20],
-		"maxDay" : int(leaderboard.CurrentBoard.MaxDay) + 1,
+		"stat": stat,
+		"stats": member_score.StatNames,
+		"ranking": strategy.Label(),
+		"topScores": topScores,
+		"maxDay" : int(snapshot.MaxDay) + 1,
+		"excluded": board.ExcludedDays[int(day)],
+		"asOf": asOf,
+		"query": query,
+		"anomalies": len(snapshot.Anomalies),
+	}
+
+	for key, value := range menuContext(board, boardUrl, baseUrl) {
+		c[key] = value
 	}
 
 	funcMap := template.FuncMap{
@@ -89,3 +112,31 @@ func Day(w http.ResponseWriter, r *http.Request) {
 		log.Printf("Error executin template: %v", err)
 	}
 }
+
+// sortMemberScores sorts memberScores by the strategy named orderBy, or by
+// fallback if there is no such strategy, and returns the strategy used.
+func sortMemberScores(memberScores []*member_score.MemberScore, orderBy string, fallback member_score.ScoringStrategy) member_score.ScoringStrategy {
+	strategy, ok := member_score.LookupStrategy(orderBy)
+	if !ok {
+		strategy = fallback
+	}
+	strategy.Rank(memberScores)
+	return strategy
+}
+
+// columnSet returns the columns of board's strategy and of the strategy
+// sorted by, for looking them up with index in the templates. Personal
+// times are shown on boards with personal starts whatever the strategy.
+func columnSet(board *leaderboard.LeaderBoard, sortedBy member_score.ScoringStrategy) map[string]bool {
+	columns := make(map[string]bool)
+	for _, column := range board.Scoring.Columns() {
+		columns[column] = true
+	}
+	for _, column := range sortedBy.Columns() {
+		columns[column] = true
+	}
+	if len(board.Starts) > 0 {
+		columns[member_score.ColumnPersonal] = true
+	}
+	return columns
+}
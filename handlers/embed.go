@@ -11,20 +11,42 @@ import (
 )
 
 func Embed(w http.ResponseWriter, r *http.Request) {
-	maxDay := int(leaderboard.CurrentBoard.MaxDay)
+	board, boardUrl, baseUrl, ok := boardFromRequest(r)
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	snapshot, asOf, query, err := snapshotFromRequest(r, board)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	maxDay := int(snapshot.MaxDay)
+
+	// Without a valid stat the totals show the total part 1 time, as they
+	// always have.
+	stat := ""
+	for _, name := range member_score.StatNames {
+		if r.URL.Query().Get("stat") == name {
+			stat = name
+		}
+	}
 
 	var totalMemberScores []*member_score.MemberScore
 	var dailyMemberScores []*member_score.MemberScore
 
-	for _, memberScore := range leaderboard.CurrentBoard.Totals {
+	for _, memberScore := range snapshot.Totals {
 		totalMemberScores = append(totalMemberScores, memberScore)
 	}
 
-	for _, memberScore := range leaderboard.CurrentBoard.Days[maxDay].MemberScores {
-		dailyMemberScores = append(dailyMemberScores, memberScore)
+	if d, ok := snapshot.Days[maxDay]; ok {
+		for _, memberScore := range d.MemberScores {
+			dailyMemberScores = append(dailyMemberScores, memberScore)
+		}
 	}
 
-	sort.Sort(member_score.ByPart2Diff(totalMemberScores))
+	board.Scoring.Rank(totalMemberScores)
 	sort.Sort(member_score.ByPart2Diff(dailyMemberScores))
 
 	if len(dailyMemberScores) > 10 {
@@ -35,19 +57,42 @@ func Embed(w http.ResponseWriter, r *http.Request) {
 		totalMemberScores = totalMemberScores[:10]
 	}
 
-	topScores := leaderboard.CurrentBoard.TopScores
+	topScores := snapshot.TopScores
 	if len(topScores) > 10 {
 		topScores = topScores[:10]
 	}
 
 	type DayScores map[string]interface{}
 	type Context map[string]interface{}
+
+	// Teams are left out of the embed of a board without any.
+	var teams interface{}
+	if standings := teamStandings(board, snapshot); len(standings) > 0 {
+		teams = DayScores{
+			"standings": standings,
+			"baseUrl": baseUrl,
+			"query": query,
+		}
+	}
+
 	c := Context{
-		"day": leaderboard.CurrentBoard.Days[maxDay].Day,
-		"year": leaderboard.CurrentBoard.Days[maxDay].Year,
+		"day": maxDay,
 		"dayScores": dailyMemberScores,
-		"totalScores": totalMemberScores,
+		"totals": DayScores{
+			"scores": totalMemberScores,
+			"columns": columnSet(board, board.Scoring),
+			"stat": stat,
+		},
+		"ranking": board.Scoring.Label(),
+		"teams": teams,
+		"teamRanking": board.TeamAggregation.Label(),
 		"topScores": topScores,
+		"asOf": asOf,
+		"query": query,
+	}
+
+	for key, value := range menuContext(board, boardUrl, baseUrl) {
+		c[key] = value
 	}
 
 	funcMap := template.FuncMap{
@@ -56,7 +101,7 @@ func Embed(w http.ResponseWriter, r *http.Request) {
 	}
 
 	tmpl := template.Must(template.New("embed.html").Funcs(funcMap).ParseGlob("templates/*.html"))
-	err := tmpl.ExecuteTemplate(w, "embed.html", c)
+	err = tmpl.ExecuteTemplate(w, "embed.html", c)
 	if err != nil {
 		log.Printf("Error executin template: %v", err)
 	}
@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"github.com/bradfitz/iter"
+	"github.com/gorilla/mux"
+	"github.com/tlj/aoc-leaderboard-go/leaderboard"
+	"github.com/tlj/aoc-leaderboard-go/member_score"
+	"html/template"
+	"log"
+	"net/http"
+	"strconv"
+)
+
+// Member shows the streaks of one member, and their times and rating on
+// each day.
+func Member(w http.ResponseWriter, r *http.Request) {
+	board, boardUrl, baseUrl, ok := boardFromRequest(r)
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	snapshot, asOf, query, err := snapshotFromRequest(r, board)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["member"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	var found *leaderboard.Member
+	if snapshot.Event != nil {
+		for _, m := range snapshot.Event.Members {
+			if m.Id == id {
+				m := m
+				found = &m
+				break
+			}
+		}
+	}
+	if found == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	name := found.Name
+	if name == "" {
+		name = "Anonymous user #" + strconv.Itoa(found.Id)
+	}
+
+	type memberDay struct {
+		Day int
+		Score *member_score.MemberScore
+		Rating int
+	}
+	total := snapshot.Totals[id]
+	var days []memberDay
+	for d := 1; d <= int(snapshot.MaxDay); d++ {
+		day := memberDay{Day: d}
+		if scores, ok := snapshot.Days[d]; ok {
+			day.Score = scores.MemberScores[id]
+		}
+		if total != nil {
+			day.Rating = total.RatingHistory[d-1]
+		}
+		days = append(days, day)
+	}
+
+	funcMap := template.FuncMap{
+		"readableTime": leaderboard.ReadableTime,
+		"N": iter.N,
+	}
+
+	type Context map[string]interface{}
+	c := Context{
+		"day": -3,
+		"maxDay": int(snapshot.MaxDay) + 1,
+		"name": name,
+		"member": found,
+		"total": total,
+		"days": days,
+		"orderBy": board.Scoring.Name(),
+		"asOf": asOf,
+		"query": query,
+	}
+
+	for key, value := range menuContext(board, boardUrl, baseUrl) {
+		c[key] = value
+	}
+
+	tmpl := template.Must(template.New("member.html").Funcs(funcMap).ParseGlob("templates/*.html"))
+	err = tmpl.ExecuteTemplate(w, "member.html", c)
+	if err != nil {
+		log.Printf("Error executin template: %v", err)
+	}
+}
@@ -0,0 +1,171 @@
+package handlers
+
+import (
+	"encoding/xml"
+	"fmt"
+	"github.com/bradfitz/iter"
+	"github.com/tlj/aoc-leaderboard-go/leaderboard"
+	"html/template"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+)
+
+type member struct {
+	Id int
+	Name string
+	Stars int
+	LocalScore int
+	JoinedAt time.Time
+}
+
+// rosterTitle describes a roster change in a sentence.
+func rosterTitle(change leaderboard.RosterChange) string {
+	name := change.Name
+	if name == "" {
+		name = "Anonymous user #" + strconv.Itoa(change.MemberId)
+	}
+	switch change.Kind {
+	case leaderboard.RosterRenamed:
+		if change.OldName == "" {
+			return fmt.Sprintf("Anonymous user #%d is now %s", change.MemberId, name)
+		}
+		return fmt.Sprintf("%s renamed themselves to %s", change.OldName, name)
+	case leaderboard.RosterLeft:
+		return fmt.Sprintf("%s left", name)
+	}
+	return fmt.Sprintf("%s joined", name)
+}
+
+// Members lists the members of a leaderboard and the changes to them
+// noticed since it has been tracked.
+func Members(w http.ResponseWriter, r *http.Request) {
+	board, boardUrl, baseUrl, ok := boardFromRequest(r)
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	snapshot := board.Snapshot()
+	roster := board.Roster()
+
+	joinedAt := make(map[int]time.Time)
+	for _, change := range roster {
+		if change.Kind == leaderboard.RosterJoined {
+			joinedAt[change.MemberId] = change.At
+		}
+	}
+
+	var members []member
+	if snapshot.Event != nil {
+		for _, m := range snapshot.Event.Members {
+			name := m.Name
+			if name == "" {
+				name = "Anonymous user #" + strconv.Itoa(m.Id)
+			}
+			members = append(members, member{
+				Id: m.Id,
+				Name: name,
+				Stars: m.Stars,
+				LocalScore: m.LocalScore,
+				JoinedAt: joinedAt[m.Id],
+			})
+		}
+	}
+	sort.Slice(members, func(i, j int) bool { return members[i].Name < members[j].Name })
+
+	type change struct {
+		At time.Time
+		Kind string
+		Title string
+	}
+	var changes []change
+	for i := len(roster) - 1; i >= 0; i-- {
+		changes = append(changes, change{roster[i].At, roster[i].Kind, rosterTitle(roster[i])})
+	}
+
+	funcMap := template.FuncMap{
+		"readableTime": leaderboard.ReadableTime,
+		"N": iter.N,
+	}
+
+	type Context map[string]interface{}
+	c := Context{
+		"day": -3,
+		"maxDay": int(snapshot.MaxDay) + 1,
+		"members": members,
+		"changes": changes,
+		"orderBy": board.Scoring.Name(),
+	}
+
+	for key, value := range menuContext(board, boardUrl, baseUrl) {
+		c[key] = value
+	}
+
+	tmpl := template.Must(template.New("members.html").Funcs(funcMap).ParseGlob("templates/*.html"))
+	err := tmpl.ExecuteTemplate(w, "members.html", c)
+	if err != nil {
+		log.Printf("Error executin template: %v", err)
+	}
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+}
+
+type atomEntry struct {
+	Id string `xml:"id"`
+	Title string `xml:"title"`
+	Updated string `xml:"updated"`
+	Link atomLink `xml:"link"`
+}
+
+type atomFeed struct {
+	XMLName xml.Name `xml:"http://www.w3.org/2005/Atom feed"`
+	Id string `xml:"id"`
+	Title string `xml:"title"`
+	Updated string `xml:"updated"`
+	Link atomLink `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+// MembersFeed serves the roster changes of a leaderboard as an Atom feed,
+// newest first.
+func MembersFeed(w http.ResponseWriter, r *http.Request) {
+	board, _, baseUrl, ok := boardFromRequest(r)
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	roster := board.Roster()
+
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	pageUrl := fmt.Sprintf("%s://%s%s/members", scheme, r.Host, baseUrl)
+
+	feed := atomFeed{
+		Id: pageUrl,
+		Title: fmt.Sprintf("Members of %s %d", board.Name, board.Year),
+		Updated: board.Status().LastSyncedAt.UTC().Format(time.RFC3339),
+		Link: atomLink{Href: pageUrl},
+	}
+	for i := len(roster) - 1; i >= 0; i-- {
+		change := roster[i]
+		feed.Entries = append(feed.Entries, atomEntry{
+			Id: fmt.Sprintf("%s#%s-%d-%d", pageUrl, change.Kind, change.MemberId, change.At.Unix()),
+			Title: rosterTitle(change),
+			Updated: change.At.UTC().Format(time.RFC3339),
+			Link: atomLink{Href: pageUrl},
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/atom+xml")
+	w.Write([]byte(xml.Header))
+	err := xml.NewEncoder(w).Encode(feed)
+	if err != nil {
+		log.Printf("Error writing members feed: %v", err)
+	}
+}
@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"sort"
+)
+
+type rankHistory struct {
+	Id int `json:"id"`
+	Name string `json:"name"`
+	Rank int `json:"rank"`
+	// History is the rank after each day, 0 before the first day done.
+	History []int `json:"history"`
+}
+
+// Ranks serves the rank history of every member of the totals as JSON,
+// best ranked first.
+func Ranks(w http.ResponseWriter, r *http.Request) {
+	board, _, _, ok := boardFromRequest(r)
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	snapshot, _, _, err := snapshotFromRequest(r, board)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	ranks := []rankHistory{}
+	for _, total := range snapshot.Totals {
+		ranks = append(ranks, rankHistory{
+			Id: total.Id,
+			Name: total.Name,
+			Rank: total.Rank,
+			History: total.RankHistory,
+		})
+	}
+	sort.Slice(ranks, func(i, j int) bool { return ranks[i].Rank < ranks[j].Rank })
+
+	w.Header().Set("Content-Type", "application/json")
+	err = json.NewEncoder(w).Encode(ranks)
+	if err != nil {
+		log.Printf("Error writing ranks: %v", err)
+	}
+}
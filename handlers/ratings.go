@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"sort"
+)
+
+type ratingHistory struct {
+	Id int `json:"id"`
+	Name string `json:"name"`
+	Rating int `json:"rating"`
+	Deviation int `json:"deviation"`
+	// History is the rating after each day, 0 before the first day played.
+	History []int `json:"history"`
+}
+
+// Ratings serves the rating history of every member of the totals as
+// JSON, highest rated first.
+func Ratings(w http.ResponseWriter, r *http.Request) {
+	board, _, _, ok := boardFromRequest(r)
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	snapshot, _, _, err := snapshotFromRequest(r, board)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	ratings := []ratingHistory{}
+	for _, total := range snapshot.Totals {
+		ratings = append(ratings, ratingHistory{
+			Id: total.Id,
+			Name: total.Name,
+			Rating: total.Rating,
+			Deviation: total.RatingDeviation,
+			History: total.RatingHistory,
+		})
+	}
+	sort.Slice(ratings, func(i, j int) bool { return ratings[i].Rating > ratings[j].Rating })
+
+	w.Header().Set("Content-Type", "application/json")
+	err = json.NewEncoder(w).Encode(ratings)
+	if err != nil {
+		log.Printf("Error writing ratings: %v", err)
+	}
+}
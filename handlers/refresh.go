@@ -0,0 +1,27 @@
+package handlers
+
+import (
+	"net/http"
+	"net/url"
+)
+
+// Refresh asks for the leaderboard to be updated right away, and sends the
+// user back to the page they came from.
+func Refresh(w http.ResponseWriter, r *http.Request) {
+	board, _, baseUrl, ok := boardFromRequest(r)
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	if err := board.RequestRefresh(); err != nil {
+		http.Error(w, err.Error(), http.StatusTooManyRequests)
+		return
+	}
+
+	back := baseUrl + "/"
+	if referer, err := url.Parse(r.Referer()); err == nil && referer.Path != "" {
+		back = referer.RequestURI()
+	}
+	http.Redirect(w, r, back, http.StatusSeeOther)
+}
@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"encoding/json"
+	"github.com/bradfitz/iter"
+	"github.com/tlj/aoc-leaderboard-go/leaderboard"
+	"github.com/tlj/aoc-leaderboard-go/member_score"
+	"html/template"
+	"log"
+	"net/http"
+)
+
+// Replay renders the page that plays the season back star by star.
+func Replay(w http.ResponseWriter, r *http.Request) {
+	board, boardUrl, baseUrl, ok := boardFromRequest(r)
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	snapshot := board.Snapshot()
+
+	funcMap := template.FuncMap{
+		"readableTime": leaderboard.ReadableTime,
+		"N": iter.N,
+	}
+
+	type Context map[string]interface{}
+	c := Context{
+		"day": -2,
+		"maxDay": int(snapshot.MaxDay) + 1,
+		"orderBy": board.Scoring.Name(),
+		"seasonStartsAt": leaderboard.Day{Year: board.Year, Day: 1}.DayStartsAt(),
+	}
+
+	for key, value := range menuContext(board, boardUrl, baseUrl) {
+		c[key] = value
+	}
+
+	tmpl := template.Must(template.New("replay.html").Funcs(funcMap).ParseGlob("templates/*.html"))
+	err := tmpl.ExecuteTemplate(w, "replay.html", c)
+	if err != nil {
+		log.Printf("Error executin template: %v", err)
+	}
+}
+
+// ReplayEvents streams the stars of the season in the order they were
+// earned, as one JSON object per line.
+func ReplayEvents(w http.ResponseWriter, r *http.Request) {
+	board, _, _, ok := boardFromRequest(r)
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	snapshot := board.Snapshot()
+	if snapshot.Event == nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	flusher, canFlush := w.(http.Flusher)
+	encoder := json.NewEncoder(w)
+	for i, event := range snapshot.Event.StarEvents() {
+		if err := encoder.Encode(event); err != nil {
+			log.Printf("Error writing replay events: %v", err)
+			return
+		}
+		if canFlush && i%100 == 99 {
+			flusher.Flush()
+		}
+	}
+}
+
+// ReplayFrame renders the totals table as of the asOf query parameter, for
+// the replay page to swap in as the season plays.
+func ReplayFrame(w http.ResponseWriter, r *http.Request) {
+	board, _, baseUrl, ok := boardFromRequest(r)
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	snapshot, _, query, err := snapshotFromRequest(r, board)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	var memberScores []*member_score.MemberScore
+	for _, memberScore := range snapshot.Totals {
+		memberScores = append(memberScores, memberScore)
+	}
+	strategy := sortMemberScores(memberScores, r.URL.Query().Get("orderBy"), board.Scoring)
+	orderBy := strategy.Name()
+
+	type DayScores map[string]interface{}
+	c := DayScores{
+		"day": int64(0),
+		"scores": memberScores,
+		"orderBy": orderBy,
+		"baseUrl": baseUrl,
+		"query": query,
+		"columns": columnSet(board, strategy),
+		"stat": member_score.StatAverage,
+		"part1OrderBy": "part1",
+		"part2OrderBy": "part2diff",
+	}
+
+	funcMap := template.FuncMap{
+		"readableTime": leaderboard.ReadableTime,
+		"N": iter.N,
+	}
+
+	tmpl := template.Must(template.New("_full_table.html").Funcs(funcMap).ParseGlob("templates/*.html"))
+	err = tmpl.ExecuteTemplate(w, "_full_table.html", c)
+	if err != nil {
+		log.Printf("Error executin template: %v", err)
+	}
+}
@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"encoding/json"
+	"github.com/bradfitz/iter"
+	"github.com/tlj/aoc-leaderboard-go/leaderboard"
+	"html/template"
+	"log"
+	"net/http"
+	"time"
+)
+
+type boardStatus struct {
+	Key string `json:"key"`
+	Id int64 `json:"id,omitempty"`
+	Year int64 `json:"year"`
+	Name string `json:"name"`
+	Archived bool `json:"archived"`
+	Stale bool `json:"stale"`
+	LastSyncedAt *time.Time `json:"last_synced_at"`
+	LastError string `json:"last_error,omitempty"`
+	LastErrorAt *time.Time `json:"last_error_at,omitempty"`
+	NextUpdateAt *time.Time `json:"next_update_at"`
+	Cookie string `json:"cookie,omitempty"`
+}
+
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+func boardStatuses() []boardStatus {
+	var statuses []boardStatus
+	for _, board := range leaderboard.Boards.AllYears() {
+		status := board.Status()
+		s := boardStatus{
+			Key: board.Key(),
+			Id: board.Id,
+			Year: board.Year,
+			Name: board.Name,
+			Archived: board.Archived,
+			Stale: status.Stale(),
+			LastSyncedAt: optionalTime(status.LastSyncedAt),
+			NextUpdateAt: optionalTime(status.NextUpdateAt),
+			Cookie: status.Cookie,
+		}
+		if status.LastError != nil {
+			s.LastError = status.LastError.Error()
+			s.LastErrorAt = optionalTime(status.LastErrorAt)
+		}
+		statuses = append(statuses, s)
+	}
+	return statuses
+}
+
+// Status shows how the updates of every leaderboard are doing.
+func Status(w http.ResponseWriter, r *http.Request) {
+	funcMap := template.FuncMap{
+		"readableTime": leaderboard.ReadableTime,
+		"N": iter.N,
+	}
+
+	type Context map[string]interface{}
+	c := Context{
+		"boards": boardStatuses(),
+	}
+
+	tmpl := template.Must(template.New("status.html").Funcs(funcMap).ParseGlob("templates/*.html"))
+	err := tmpl.ExecuteTemplate(w, "status.html", c)
+	if err != nil {
+		log.Printf("Error executin template: %v", err)
+	}
+}
+
+// StatusJson serves the same information as Status as JSON.
+func StatusJson(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	err := json.NewEncoder(w).Encode(boardStatuses())
+	if err != nil {
+		log.Printf("Error writing status: %v", err)
+	}
+}
@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"github.com/bradfitz/iter"
+	"github.com/gorilla/mux"
+	"github.com/tlj/aoc-leaderboard-go/leaderboard"
+	"github.com/tlj/aoc-leaderboard-go/member_score"
+	"html/template"
+	"log"
+	"net/http"
+)
+
+// teamStandings ranks the teams of board with members on it in snapshot.
+// Members who have no totals, as no day counts for them yet, still bring
+// the stars and points of their days.
+func teamStandings(board *leaderboard.LeaderBoard, snapshot *leaderboard.Snapshot) []*member_score.TeamScore {
+	present := make(map[int]bool)
+	if snapshot.Event != nil {
+		for _, m := range snapshot.Event.Members {
+			present[m.Id] = true
+		}
+	}
+
+	scores := make(map[int]*member_score.MemberScore)
+	for id, total := range snapshot.Totals {
+		scores[id] = total
+	}
+	for _, day := range snapshot.Days {
+		if day.Excluded {
+			continue
+		}
+		for id, ms := range day.MemberScores {
+			if _, ok := snapshot.Totals[id]; ok {
+				continue
+			}
+			score, ok := scores[id]
+			if !ok {
+				score = &member_score.MemberScore{Id: id, Name: ms.Name}
+				scores[id] = score
+			}
+			score.Stars += ms.Stars
+			score.Points += ms.Points
+		}
+	}
+
+	return member_score.TeamStandings(board.Teams, present, scores, board.TeamAggregation)
+}
+
+// Teams shows the standings of the teams.
+func Teams(w http.ResponseWriter, r *http.Request) {
+	renderTeams(w, r, "")
+}
+
+// Team shows the members of one team and how they make up its score.
+func Team(w http.ResponseWriter, r *http.Request) {
+	renderTeams(w, r, mux.Vars(r)["team"])
+}
+
+func renderTeams(w http.ResponseWriter, r *http.Request, name string) {
+	board, boardUrl, baseUrl, ok := boardFromRequest(r)
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	snapshot, asOf, query, err := snapshotFromRequest(r, board)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	standings := teamStandings(board, snapshot)
+
+	var team *member_score.TeamScore
+	if name != "" {
+		for _, ts := range standings {
+			if ts.Name == name {
+				team = ts
+			}
+		}
+		if team == nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+	}
+
+	funcMap := template.FuncMap{
+		"readableTime": leaderboard.ReadableTime,
+		"N": iter.N,
+	}
+
+	type Context map[string]interface{}
+	c := Context{
+		"day": -4,
+		"maxDay": int(snapshot.MaxDay) + 1,
+		"standings": standings,
+		"team": team,
+		"ranking": board.TeamAggregation.Label(),
+		"orderBy": board.Scoring.Name(),
+		"asOf": asOf,
+		"query": query,
+	}
+
+	for key, value := range menuContext(board, boardUrl, baseUrl) {
+		c[key] = value
+	}
+
+	tmpl := template.Must(template.New("teams.html").Funcs(funcMap).ParseGlob("templates/*.html"))
+	err = tmpl.ExecuteTemplate(w, "teams.html", c)
+	if err != nil {
+		log.Printf("Error executin template: %v", err)
+	}
+}
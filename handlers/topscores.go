@@ -9,6 +9,17 @@ import (
 )
 
 func TopScores(w http.ResponseWriter, r *http.Request) {
+	board, boardUrl, baseUrl, ok := boardFromRequest(r)
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	snapshot, asOf, query, err := snapshotFromRequest(r, board)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
 	funcMap := template.FuncMap{
 		"readableTime": leaderboard.ReadableTime,
 		"N": iter.N,
@@ -17,13 +28,19 @@ func TopScores(w http.ResponseWriter, r *http.Request) {
 	type Context map[string]interface{}
 	c := Context{
 		"day": -1,
-		"maxDay": int(leaderboard.CurrentBoard.MaxDay),
-		"year": leaderboard.CurrentBoard.Year,
-		"topScores": leaderboard.CurrentBoard.TopScores,
+		"maxDay": int(snapshot.MaxDay),
+		"topScores": snapshot.TopScores,
+		"asOf": asOf,
+		"query": query,
+		"anomalies": len(snapshot.Anomalies),
+	}
+
+	for key, value := range menuContext(board, boardUrl, baseUrl) {
+		c[key] = value
 	}
 
 	tmpl := template.Must(template.New("topscores.html").Funcs(funcMap).ParseGlob("templates/*.html"))
-	err := tmpl.ExecuteTemplate(w, "topscores.html", c)
+	err = tmpl.ExecuteTemplate(w, "topscores.html", c)
 	if err != nil {
 		log.Printf("Error executin template: %v", err)
 	}
@@ -0,0 +1,90 @@
+package leaderboard
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+)
+
+// An Alerter tells an admin about problems with a leaderboard that won't
+// go away by themselves.
+type Alerter interface {
+	Alert(l *LeaderBoard, message string)
+}
+
+// LogAlerter writes alerts to the log.
+type LogAlerter struct{}
+
+func (LogAlerter) Alert(l *LeaderBoard, message string) {
+	log.Printf("ALERT leaderboard %d year %d: %s", l.Id, l.Year, message)
+}
+
+// WebhookAlerter posts alerts as {"text": "..."} to a URL, which is what
+// Slack and Mattermost incoming webhooks expect.
+type WebhookAlerter struct {
+	Url string
+}
+
+func (a WebhookAlerter) Alert(l *LeaderBoard, message string) {
+	LogAlerter{}.Alert(l, message)
+
+	body, err := json.Marshal(map[string]string{
+		"text": fmt.Sprintf("AoC leaderboard %s (%d, %d): %s", l.Name, l.Id, l.Year, message),
+	})
+	if err != nil {
+		log.Printf("Error encoding alert: %v", err)
+		return
+	}
+
+	go func() {
+		client := http.Client{Timeout: 10 * time.Second}
+		resp, err := client.Post(a.Url, "application/json", bytes.NewReader(body))
+		if err != nil {
+			log.Printf("Error sending alert: %v", err)
+			return
+		}
+		resp.Body.Close()
+		if resp.StatusCode >= 300 {
+			log.Printf("Error sending alert: status %d", resp.StatusCode)
+		}
+	}()
+}
+
+// checkAlerts raises an alert the first time the session cookie is
+// rejected, and the first time the data has been stale for longer than
+// StaleAlertAfter. Once an update succeeds again, it says so.
+func (l *LeaderBoard) checkAlerts(err error) {
+	if l.Alerter == nil {
+		return
+	}
+
+	var messages []string
+
+	l.mu.Lock()
+	if err == nil {
+		if l.authAlerted || l.staleAlerted {
+			messages = append(messages, "updates are working again")
+		}
+		l.authAlerted = false
+		l.staleAlerted = false
+	} else {
+		if IsAuthError(err) && !l.authAlerted {
+			l.authAlerted = true
+			messages = append(messages, fmt.Sprintf("the session cookie was rejected (%v), it has probably expired", err))
+		}
+
+		since := l.snapshot.LastSyncedAt
+		if l.StaleAlertAfter > 0 && !since.IsZero() && time.Since(since) > l.StaleAlertAfter && !l.staleAlerted {
+			l.staleAlerted = true
+			messages = append(messages, fmt.Sprintf("no successful update since %s, last error: %v", since.Format(time.RFC3339), err))
+		}
+	}
+	l.mu.Unlock()
+
+	for _, message := range messages {
+		l.Alerter.Alert(l, message)
+	}
+}
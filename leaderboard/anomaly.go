@@ -0,0 +1,107 @@
+package leaderboard
+
+import (
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Reasons for a star to be reported as an Anomaly.
+const (
+	AnomalyBeforeDayStart = "earned before the day unlocked"
+	AnomalyPart2BeforePart1 = "part 2 earned before part 1"
+	AnomalyPart2WithoutPart1 = "part 2 earned without part 1"
+	AnomalyOutsideEvent = "day is not part of the event"
+	AnomalyInFuture = "earned in the future"
+)
+
+// An Anomaly is a star with a timestamp that can't be right. Anomalies are
+// left out of the scores.
+type Anomaly struct {
+	MemberId int
+	Name string
+	Day int
+	Part int
+	Ts int64
+	Reason string
+}
+
+// Validate returns a copy of the event without the stars that have
+// impossible timestamps, and the anomalies found. Stars and scores reported
+// by AoC are left as they are.
+func (e *Event) Validate(year int64, now time.Time) (*Event, []Anomaly) {
+	numDays := e.NumDays
+	if numDays == 0 {
+		numDays = 25
+	}
+
+	valid := *e
+	valid.Members = make(map[string]Member)
+	var anomalies []Anomaly
+
+	for key, member := range e.Members {
+		name := member.Name
+		if name == "" {
+			name = strconv.Itoa(member.Id)
+		}
+		flag := func(day int, part int, reason string) {
+			anomalies = append(anomalies, Anomaly{
+				MemberId: member.Id,
+				Name: name,
+				Day: day,
+				Part: part,
+				Ts: int64(member.CompletionDayLevels[day][part].GetStarTs),
+				Reason: reason,
+			})
+		}
+
+		levels := make(map[int]map[int]Star)
+		for day, parts := range member.CompletionDayLevels {
+			dayStartsAt := Day{Year: year, Day: day}.DayStartsAt()
+			kept := make(map[int]Star)
+
+			for part, star := range parts {
+				switch {
+				case day < 1 || day > numDays:
+					flag(day, part, AnomalyOutsideEvent)
+				case int64(star.GetStarTs) < dayStartsAt:
+					flag(day, part, AnomalyBeforeDayStart)
+				case int64(star.GetStarTs) > now.Unix():
+					flag(day, part, AnomalyInFuture)
+				default:
+					kept[part] = star
+				}
+			}
+
+			if part2, ok := kept[2]; ok {
+				part1, ok := kept[1]
+				if !ok {
+					flag(day, 2, AnomalyPart2WithoutPart1)
+					delete(kept, 2)
+				} else if part2.GetStarTs < part1.GetStarTs {
+					flag(day, 2, AnomalyPart2BeforePart1)
+					delete(kept, 2)
+				}
+			}
+
+			if len(kept) > 0 {
+				levels[day] = kept
+			}
+		}
+
+		member.CompletionDayLevels = levels
+		valid.Members[key] = member
+	}
+
+	sort.Slice(anomalies, func(i, j int) bool {
+		if anomalies[i].Day != anomalies[j].Day {
+			return anomalies[i].Day < anomalies[j].Day
+		}
+		if anomalies[i].Name != anomalies[j].Name {
+			return anomalies[i].Name < anomalies[j].Name
+		}
+		return anomalies[i].Part < anomalies[j].Part
+	})
+
+	return &valid, anomalies
+}
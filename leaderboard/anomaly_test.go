@@ -0,0 +1,91 @@
+package leaderboard
+
+import (
+	"testing"
+	"time"
+)
+
+func TestValidate(t *testing.T) {
+	day1 := Day{Year: 2018, Day: 1}.DayStartsAt()
+	day2 := Day{Year: 2018, Day: 2}.DayStartsAt()
+	now := time.Unix(day2+60*60, 0)
+	star := func(ts int64) Star { return Star{GetStarTs: FlexInt(ts)} }
+
+	tests := []struct {
+		name string
+		numDays int
+		levels map[int]map[int]Star
+		kept map[int][]int
+		reasons []string
+	}{
+		{
+			name: "valid",
+			levels: map[int]map[int]Star{1: {1: star(day1 + 60), 2: star(day1 + 120)}, 2: {1: star(day2 + 60)}},
+			kept: map[int][]int{1: {1, 2}, 2: {1}},
+		},
+		{
+			name: "before the day unlocked",
+			levels: map[int]map[int]Star{2: {1: star(day2 - 60), 2: star(day2 + 60)}},
+			kept: map[int][]int{},
+			reasons: []string{AnomalyBeforeDayStart, AnomalyPart2WithoutPart1},
+		},
+		{
+			name: "part 2 before part 1",
+			levels: map[int]map[int]Star{1: {1: star(day1 + 120), 2: star(day1 + 60)}},
+			kept: map[int][]int{1: {1}},
+			reasons: []string{AnomalyPart2BeforePart1},
+		},
+		{
+			name: "in the future",
+			levels: map[int]map[int]Star{2: {1: star(day2 + 60), 2: star(day2 + 2*60*60)}},
+			kept: map[int][]int{2: {1}},
+			reasons: []string{AnomalyInFuture},
+		},
+		{
+			name: "outside the event",
+			numDays: 1,
+			levels: map[int]map[int]Star{1: {1: star(day1 + 60)}, 2: {1: star(day2 + 60)}},
+			kept: map[int][]int{1: {1}},
+			reasons: []string{AnomalyOutsideEvent},
+		},
+	}
+
+	for _, test := range tests {
+		event := &Event{
+			NumDays: test.numDays,
+			Members: map[string]Member{"7": {Id: 7, Name: "Seven", Stars: 3, CompletionDayLevels: test.levels}},
+		}
+		valid, anomalies := event.Validate(2018, now)
+
+		member := valid.Members["7"]
+		if len(member.CompletionDayLevels) != len(test.kept) {
+			t.Errorf("%s: kept %d days, want %d", test.name, len(member.CompletionDayLevels), len(test.kept))
+		}
+		for day, parts := range test.kept {
+			if len(member.CompletionDayLevels[day]) != len(parts) {
+				t.Errorf("%s: kept %d parts of day %d, want %d", test.name, len(member.CompletionDayLevels[day]), day, len(parts))
+			}
+			for _, part := range parts {
+				if _, ok := member.CompletionDayLevels[day][part]; !ok {
+					t.Errorf("%s: part %d of day %d left out", test.name, part, day)
+				}
+			}
+		}
+		if member.Stars != 3 {
+			t.Errorf("%s: stars changed to %d", test.name, member.Stars)
+		}
+		if len(event.Members["7"].CompletionDayLevels) != len(test.levels) {
+			t.Errorf("%s: the event validated was changed", test.name)
+		}
+
+		if len(anomalies) != len(test.reasons) {
+			t.Errorf("%s: got %d anomalies, want %d", test.name, len(anomalies), len(test.reasons))
+			continue
+		}
+		for i, anomaly := range anomalies {
+			if anomaly.Reason != test.reasons[i] || anomaly.MemberId != 7 {
+				t.Errorf("%s: got anomaly %+v, want reason %q", test.name, anomaly, test.reasons[i])
+			}
+		}
+	}
+}
@@ -0,0 +1,261 @@
+package leaderboard
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// The versions of the leaderboard JSON known to DecodeEvent.
+const (
+	// SchemaV1 is the format used up to 2019, with ids and timestamps
+	// mostly given as strings.
+	SchemaV1 = 1
+	// SchemaV2 adds day1_ts, num_days and star_index, and gives ids and
+	// timestamps as numbers.
+	SchemaV2 = 2
+)
+
+var (
+	warnedMu sync.Mutex
+	warned = make(map[string]bool)
+)
+
+// warnUnknown logs a key DecodeEvent doesn't know, once per key.
+func warnUnknown(path string) {
+	warnedMu.Lock()
+	defer warnedMu.Unlock()
+	if warned[path] {
+		return
+	}
+	warned[path] = true
+	log.Printf("Unknown key %s in leaderboard, ignoring it.", path)
+}
+
+// decodeObject decodes a JSON object into its raw fields. null decodes to
+// no fields.
+func decodeObject(data json.RawMessage) (map[string]json.RawMessage, error) {
+	fields := make(map[string]json.RawMessage)
+	if isNull(data) {
+		return fields, nil
+	}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+func isNull(data json.RawMessage) bool {
+	return len(data) == 0 || bytes.Equal(bytes.TrimSpace(data), []byte("null"))
+}
+
+// decodeInt decodes a number, a string holding a number, or null as 0.
+func decodeInt(data json.RawMessage) (int64, error) {
+	if isNull(data) {
+		return 0, nil
+	}
+	var i FlexInt
+	if err := json.Unmarshal(data, &i); err != nil {
+		return 0, err
+	}
+	return int64(i), nil
+}
+
+// decodeString decodes a string, a number as its decimal string, or null
+// as "".
+func decodeString(data json.RawMessage) (string, error) {
+	if isNull(data) {
+		return "", nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		return s, nil
+	}
+	var n json.Number
+	if err := json.Unmarshal(data, &n); err != nil {
+		return "", err
+	}
+	return n.String(), nil
+}
+
+// sortedKeys returns the keys of fields in order, so unknown keys are
+// reported the same way every time.
+func sortedKeys(fields map[string]json.RawMessage) []string {
+	var keys []string
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// DecodeEvent decodes a leaderboard in any of the known schema versions.
+// Numbers given as strings and null values are accepted wherever they have
+// been seen, and unknown keys are logged instead of failing the decoding.
+func DecodeEvent(body []byte) (*Event, error) {
+	fields, err := decodeObject(body)
+	if err != nil {
+		return nil, err
+	}
+
+	event := &Event{
+		Members: make(map[string]Member),
+		SchemaVersion: SchemaV1,
+	}
+
+	for _, key := range sortedKeys(fields) {
+		value := fields[key]
+		switch key {
+		case "event", "year":
+			event.Year, err = decodeString(value)
+		case "owner_id":
+			event.OwnerId, err = decodeString(value)
+		case "day1_ts":
+			event.SchemaVersion = SchemaV2
+			event.Day1Ts, err = decodeInt(value)
+		case "num_days":
+			event.SchemaVersion = SchemaV2
+			var numDays int64
+			numDays, err = decodeInt(value)
+			event.NumDays = int(numDays)
+		case "members":
+			err = decodeMembers(value, event)
+		default:
+			warnUnknown(key)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", key, err)
+		}
+	}
+
+	return event, nil
+}
+
+func decodeMembers(data json.RawMessage, event *Event) error {
+	members, err := decodeObject(data)
+	if err != nil {
+		return err
+	}
+
+	for key, value := range members {
+		member, err := decodeMember(value, event)
+		if err != nil {
+			return fmt.Errorf("%s: %v", key, err)
+		}
+		// Members are keyed by their id, which some payloads leave out of
+		// the member itself.
+		if member.Id == 0 {
+			member.Id, _ = strconv.Atoi(key)
+		}
+		event.Members[key] = member
+	}
+
+	return nil
+}
+
+func decodeMember(data json.RawMessage, event *Event) (Member, error) {
+	member := Member{}
+	fields, err := decodeObject(data)
+	if err != nil {
+		return member, err
+	}
+
+	for _, key := range sortedKeys(fields) {
+		value := fields[key]
+		var i int64
+		switch key {
+		case "id":
+			i, err = decodeInt(value)
+			member.Id = int(i)
+		case "name":
+			member.Name, err = decodeString(value)
+		case "stars":
+			i, err = decodeInt(value)
+			member.Stars = int(i)
+		case "local_score":
+			i, err = decodeInt(value)
+			member.LocalScore = int(i)
+		case "global_score":
+			i, err = decodeInt(value)
+			member.GlobalScore = int(i)
+		case "last_star_ts":
+			i, err = decodeInt(value)
+			member.LastStarTs = FlexInt(i)
+		case "completion_day_level":
+			member.CompletionDayLevels, err = decodeDayLevels(value, event)
+		default:
+			warnUnknown("members.*." + key)
+		}
+		if err != nil {
+			return member, fmt.Errorf("%s: %v", key, err)
+		}
+	}
+
+	return member, nil
+}
+
+func decodeDayLevels(data json.RawMessage, event *Event) (map[int]map[int]Star, error) {
+	levels := make(map[int]map[int]Star)
+	days, err := decodeObject(data)
+	if err != nil {
+		return nil, err
+	}
+
+	for dayKey, dayValue := range days {
+		day, err := strconv.Atoi(dayKey)
+		if err != nil {
+			return nil, fmt.Errorf("day %q: %v", dayKey, err)
+		}
+		parts, err := decodeObject(dayValue)
+		if err != nil {
+			return nil, fmt.Errorf("day %d: %v", day, err)
+		}
+
+		levels[day] = make(map[int]Star)
+		for partKey, partValue := range parts {
+			part, err := strconv.Atoi(partKey)
+			if err != nil {
+				return nil, fmt.Errorf("day %d part %q: %v", day, partKey, err)
+			}
+			star, err := decodeStar(partValue, event)
+			if err != nil {
+				return nil, fmt.Errorf("day %d part %d: %v", day, part, err)
+			}
+			levels[day][part] = star
+		}
+	}
+
+	return levels, nil
+}
+
+func decodeStar(data json.RawMessage, event *Event) (Star, error) {
+	star := Star{}
+	fields, err := decodeObject(data)
+	if err != nil {
+		return star, err
+	}
+
+	for _, key := range sortedKeys(fields) {
+		value := fields[key]
+		var i int64
+		switch key {
+		case "get_star_ts":
+			i, err = decodeInt(value)
+			star.GetStarTs = FlexInt(i)
+		case "star_index":
+			event.SchemaVersion = SchemaV2
+			star.StarIndex, err = decodeInt(value)
+		default:
+			warnUnknown("members.*.completion_day_level.*.*." + key)
+		}
+		if err != nil {
+			return star, fmt.Errorf("%s: %v", key, err)
+		}
+	}
+
+	return star, nil
+}
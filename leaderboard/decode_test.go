@@ -0,0 +1,122 @@
+package leaderboard
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDecodeEvent(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		schema int
+		year string
+		owner string
+		memberKey string
+		member Member
+		starTs FlexInt
+		starIndex int64
+	}{
+		{
+			name: "v1 with strings",
+			body: `{"event":"2018","owner_id":"116603","members":{"116603":{"id":"116603","name":"Thomas","stars":2,
+				"local_score":10,"global_score":0,"last_star_ts":"1543645000",
+				"completion_day_level":{"1":{"1":{"get_star_ts":"1543640000"},"2":{"get_star_ts":"1543645000"}}}}}}`,
+			schema: SchemaV1,
+			year: "2018",
+			owner: "116603",
+			memberKey: "116603",
+			member: Member{Id: 116603, Name: "Thomas", Stars: 2, LocalScore: 10, LastStarTs: 1543645000},
+			starTs: 1543640000,
+		},
+		{
+			name: "v2 with numbers",
+			body: `{"event":"2023","owner_id":116603,"day1_ts":1701406800,"num_days":25,"members":{"116603":{"id":116603,
+				"name":"Thomas","stars":1,"local_score":5,"global_score":0,"last_star_ts":1701410000,
+				"completion_day_level":{"1":{"1":{"get_star_ts":1701410000,"star_index":42}}}}}}`,
+			schema: SchemaV2,
+			year: "2023",
+			owner: "116603",
+			memberKey: "116603",
+			member: Member{Id: 116603, Name: "Thomas", Stars: 1, LocalScore: 5, LastStarTs: 1701410000},
+			starTs: 1701410000,
+			starIndex: 42,
+		},
+		{
+			name: "null name and id from key",
+			body: `{"event":"2018","members":{"42":{"name":null,"stars":1,"local_score":1,"last_star_ts":0,
+				"completion_day_level":{"1":{"1":{"get_star_ts":"1543640000"}}}}}}`,
+			schema: SchemaV1,
+			year: "2018",
+			memberKey: "42",
+			member: Member{Id: 42, Stars: 1, LocalScore: 1},
+			starTs: 1543640000,
+		},
+		{
+			name: "year instead of event",
+			body: `{"year":2019,"members":{"7":{"id":7,"name":"Seven","completion_day_level":{"1":{"1":{"get_star_ts":1575180000}}}}}}`,
+			schema: SchemaV1,
+			year: "2019",
+			memberKey: "7",
+			member: Member{Id: 7, Name: "Seven"},
+			starTs: 1575180000,
+		},
+		{
+			name: "unknown keys",
+			body: `{"event":"2018","unknown_event_key":[1,2],"members":{"7":{"id":7,"name":"Seven","unknown_member_key":{},
+				"completion_day_level":{"1":{"1":{"get_star_ts":1543640000,"unknown_star_key":true}}}}}}`,
+			schema: SchemaV1,
+			year: "2018",
+			memberKey: "7",
+			member: Member{Id: 7, Name: "Seven"},
+			starTs: 1543640000,
+		},
+	}
+
+	for _, test := range tests {
+		event, err := DecodeEvent([]byte(test.body))
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		if event.SchemaVersion != test.schema || event.Year != test.year || event.OwnerId != test.owner {
+			t.Errorf("%s: got schema %d, year %q and owner %q, want %d, %q and %q", test.name,
+				event.SchemaVersion, event.Year, event.OwnerId, test.schema, test.year, test.owner)
+		}
+
+		member, ok := event.Members[test.memberKey]
+		if !ok {
+			t.Errorf("%s: no member %s", test.name, test.memberKey)
+			continue
+		}
+		star := member.CompletionDayLevels[1][1]
+		member.CompletionDayLevels = nil
+		if !reflect.DeepEqual(member, test.member) {
+			t.Errorf("%s: got member %+v, want %+v", test.name, member, test.member)
+		}
+		if star.GetStarTs != test.starTs || star.StarIndex != test.starIndex {
+			t.Errorf("%s: got star at %d with index %d, want %d with index %d", test.name,
+				star.GetStarTs, star.StarIndex, test.starTs, test.starIndex)
+		}
+	}
+
+	for _, key := range []string{"unknown_event_key", "members.*.unknown_member_key", "members.*.completion_day_level.*.*.unknown_star_key"} {
+		if !warned[key] {
+			t.Errorf("unknown key %s wasn't warned about", key)
+		}
+	}
+}
+
+func TestDecodeEventErrors(t *testing.T) {
+	bodies := []string{
+		`[]`,
+		`{"members":{"7":{"stars":"many"}}}`,
+		`{"members":{"7":{"completion_day_level":{"first":{}}}}}`,
+		`{"members":{"7":{"completion_day_level":{"1":{"1":{"get_star_ts":"soon"}}}}}}`,
+	}
+	for _, body := range bodies {
+		if _, err := DecodeEvent([]byte(body)); err == nil {
+			t.Errorf("decoding %s succeeded, want an error", body)
+		}
+	}
+}
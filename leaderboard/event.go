@@ -2,7 +2,9 @@ package leaderboard
 
 import (
 	"encoding/json"
+	"sort"
 	"strconv"
+	"time"
 )
 
 // A FlexInt is an int that can be unmarshalled from a JSON field
@@ -27,18 +29,184 @@ func (fi *FlexInt) UnmarshalJSON(b []byte) error {
 	return nil
 }
 
+type Star struct {
+	GetStarTs FlexInt `json:"get_star_ts"`
+	// StarIndex orders the stars earned in the same second. It is only
+	// given by SchemaV2 payloads.
+	StarIndex int64 `json:"star_index,omitempty"`
+}
+
+type Member struct {
+	GlobalScore int `json:"global_score"`
+	LocalScore int `json:"local_score"`
+	Name string `json:"name"`
+	Stars int `json:"stars"`
+	CompletionDayLevels map[int]map[int]Star `json:"completion_day_level"`
+	LastStarTs FlexInt `json:"last_star_ts"`
+	Id int `json:"id,string"`
+}
+
+// An Event is a private leaderboard of one year. Use DecodeEvent rather
+// than json.Unmarshal to decode one, since the format has changed over the
+// years.
 type Event struct {
-	Year string
-	Members map[string]struct {
-		GlobalScore int `json:"global_score"`
-		LocalScore int `json:"local_score"`
-		Name string `json:"name"`
-		Stars int `json:"stars"`
-		CompletionDayLevels map[int]map[int]struct {
-			GetStarTs FlexInt `json:"get_star_ts"`
-		} `json:"completion_day_level"`
-		LastStarTs FlexInt `json:"last_star_ts"`
-		Id int `json:"id,string"`
-	}
+	Year string `json:"event"`
+	Members map[string]Member `json:"members"`
 	OwnerId string `json:"owner_id"`
+	// Day1Ts is when the first day unlocked, and NumDays the number of days
+	// of the event. Both are only given by SchemaV2 payloads.
+	Day1Ts int64 `json:"day1_ts,omitempty"`
+	NumDays int `json:"num_days,omitempty"`
+	// SchemaVersion is the version of the payload the event was decoded
+	// from.
+	SchemaVersion int `json:"-"`
+}
+
+// Before returns a copy of the event holding only the stars earned before
+// t, with the stars, last star and local score of each member recalculated.
+// Global scores can't be recalculated and are left as they are.
+func (e *Event) Before(t time.Time) *Event {
+	before := &Event{
+		Year: e.Year,
+		OwnerId: e.OwnerId,
+		Day1Ts: e.Day1Ts,
+		NumDays: e.NumDays,
+		SchemaVersion: e.SchemaVersion,
+		Members: make(map[string]Member),
+	}
+
+	for key, member := range e.Members {
+		levels := make(map[int]map[int]Star)
+		member.Stars = 0
+		member.LastStarTs = 0
+
+		for day, parts := range member.CompletionDayLevels {
+			for part, star := range parts {
+				if int64(star.GetStarTs) >= t.Unix() {
+					continue
+				}
+				if _, ok := levels[day]; !ok {
+					levels[day] = make(map[int]Star)
+				}
+				levels[day][part] = star
+				member.Stars++
+				if star.GetStarTs > member.LastStarTs {
+					member.LastStarTs = star.GetStarTs
+				}
+			}
+		}
+
+		member.CompletionDayLevels = levels
+		before.Members[key] = member
+	}
+
+	for key, score := range before.localScores() {
+		member := before.Members[key]
+		member.LocalScore = score
+		before.Members[key] = member
+	}
+
+	return before
+}
+
+// localScores calculates the local score of each member the way AoC does,
+// as the sum of their dayPoints.
+func (e *Event) localScores() map[string]int {
+	scores := make(map[string]int)
+	for key, days := range e.dayPoints() {
+		for _, points := range days {
+			scores[key] += points
+		}
+	}
+	return scores
+}
+
+// dayPoints calculates the local points each member earned on each day:
+// for every star, the first member to get it gets as many points as there
+// are members, the second one point less, and so on. Stars earned in the
+// same second are ordered by their StarIndex, and then by member id, as
+// older payloads don't have one.
+func (e *Event) dayPoints() map[string]map[int]int {
+	type starTs struct {
+		key string
+		id int
+		ts FlexInt
+		index int64
+	}
+
+	stars := make(map[[2]int][]starTs)
+	for key, member := range e.Members {
+		for day, parts := range member.CompletionDayLevels {
+			for part, star := range parts {
+				stars[[2]int{day, part}] = append(stars[[2]int{day, part}], starTs{key, member.Id, star.GetStarTs, star.StarIndex})
+			}
+		}
+	}
+
+	points := make(map[string]map[int]int)
+	for key := range e.Members {
+		points[key] = make(map[int]int)
+	}
+	for dayPart, list := range stars {
+		sort.Slice(list, func(i, j int) bool {
+			if list[i].ts != list[j].ts {
+				return list[i].ts < list[j].ts
+			}
+			if list[i].index != list[j].index {
+				return list[i].index < list[j].index
+			}
+			return list[i].id < list[j].id
+		})
+		for i, star := range list {
+			points[star.key][dayPart[0]] += len(e.Members) - i
+		}
+	}
+
+	return points
+}
+
+// A StarEvent is a single star earned by a member.
+type StarEvent struct {
+	Ts int64 `json:"ts"`
+	MemberId int `json:"member_id"`
+	Name string `json:"name"`
+	Day int `json:"day"`
+	Part int `json:"part"`
+	StarIndex int64 `json:"-"`
+}
+
+// StarEvents returns every star earned in the event in the order they were
+// earned.
+func (e *Event) StarEvents() []StarEvent {
+	var events []StarEvent
+	for _, member := range e.Members {
+		name := member.Name
+		if name == "" {
+			name = strconv.Itoa(member.Id)
+		}
+		for day, parts := range member.CompletionDayLevels {
+			for part, star := range parts {
+				events = append(events, StarEvent{
+					Ts: int64(star.GetStarTs),
+					MemberId: member.Id,
+					Name: name,
+					Day: day,
+					Part: part,
+					StarIndex: star.StarIndex,
+				})
+			}
+		}
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		if events[i].Ts == events[j].Ts {
+			if events[i].StarIndex != events[j].StarIndex {
+				return events[i].StarIndex < events[j].StarIndex
+			}
+			return events[i].Part < events[j].Part
+		}
+		return events[i].Ts < events[j].Ts
+	})
+
+	return events
 }
@@ -0,0 +1,155 @@
+package leaderboard
+
+import (
+	"github.com/tlj/aoc-leaderboard-go/member_score"
+	"io/ioutil"
+	"sync"
+	"testing"
+	"time"
+)
+
+// loadFixture decodes the recorded 2018 leaderboard in testdata.
+func loadFixture(t *testing.T) *Event {
+	body, err := ioutil.ReadFile("testdata/fixture.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	event, err := DecodeEvent(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return event
+}
+
+func TestNewSnapshot(t *testing.T) {
+	event := loadFixture(t)
+	snapshot := NewSnapshot(2018, event, Rules{Scoring: member_score.DefaultStrategy}, time.Now())
+
+	if snapshot.MaxDay != 9 {
+		t.Errorf("MaxDay = %d, want 9", snapshot.MaxDay)
+	}
+	if len(snapshot.Anomalies) != 0 {
+		t.Errorf("got %d anomalies, want none", len(snapshot.Anomalies))
+	}
+	if len(snapshot.Totals) != 26 {
+		t.Errorf("got %d totals, want 26", len(snapshot.Totals))
+	}
+
+	for _, m := range event.Members {
+		total, ok := snapshot.Totals[m.Id]
+		if !ok {
+			continue
+		}
+		if total.Stars != m.Stars {
+			t.Errorf("member %d has %d stars, want %d", m.Id, total.Stars, m.Stars)
+		}
+	}
+
+	ranks := []struct {
+		id int
+		rank int
+		count int64
+		points int
+	}{
+		{245916, 1, 9, 845},
+		{201045, 2, 9, 845},
+		{276702, 9, 8, 496},
+		{437241, 26, 1, 56},
+	}
+	for _, want := range ranks {
+		total, ok := snapshot.Totals[want.id]
+		if !ok {
+			t.Errorf("member %d has no totals", want.id)
+			continue
+		}
+		if total.Rank != want.rank || total.Count != want.count || total.Points != want.points {
+			t.Errorf("member %d has rank %d, %d days and %d points, want rank %d, %d days and %d points",
+				want.id, total.Rank, total.Count, total.Points, want.rank, want.count, want.points)
+		}
+	}
+}
+
+func TestUpdateFromSource(t *testing.T) {
+	body, err := ioutil.ReadFile("testdata/fixture.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	board := NewLeaderBoard(2018, 1, "Fixture", FixtureSource{Data: body})
+	if err := board.UpdateFromSource(); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(board.Snapshot().Totals); n != 26 {
+		t.Errorf("got %d totals, want 26", n)
+	}
+}
+
+// TestUpdateScoresRace publishes snapshots while they are read, which
+// go test -race checks for unsynchronized access.
+func TestUpdateScoresRace(t *testing.T) {
+	event := loadFixture(t)
+	board := NewLeaderBoard(2018, 1, "Fixture", FixtureSource{})
+	board.UpdateScores(event, time.Now())
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer close(done)
+		for i := 0; i < 20; i++ {
+			board.UpdateScores(event, time.Now())
+		}
+	}()
+
+	asOf := time.Date(2018, 12, 5, 5, 0, 0, 0, time.UTC)
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				if board.Snapshot().MaxDay != 9 {
+					t.Error("Snapshot isn't up to day 9")
+				}
+				if board.SnapshotAt(asOf).MaxDay != 4 {
+					t.Error("SnapshotAt isn't up to day 4")
+				}
+				if board.Status().LastSyncedAt.IsZero() {
+					t.Error("Status has no LastSyncedAt")
+				}
+				select {
+				case <-done:
+					return
+				default:
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// TestSnapshotAtDayInProgress looks back at an hour after day 9 unlocked,
+// when members who haven't done it yet still have their streaks.
+func TestSnapshotAtDayInProgress(t *testing.T) {
+	board := NewLeaderBoard(2018, 1, "Fixture", FixtureSource{})
+	board.UpdateScores(loadFixture(t), time.Now())
+
+	snapshot := board.SnapshotAt(time.Date(2018, 12, 9, 6, 0, 0, 0, time.UTC))
+	if snapshot.MaxDay != 9 {
+		t.Fatalf("MaxDay = %d, want 9", snapshot.MaxDay)
+	}
+
+	checked := 0
+	for id, total := range snapshot.Totals {
+		day8, ok := snapshot.Days[8].MemberScores[id]
+		if !ok || day8.Part2 == 0 {
+			continue
+		}
+		checked++
+		if total.Streaks.Current == 0 {
+			t.Errorf("member %d did day 8 but has no current streak", id)
+		}
+	}
+	if checked == 0 {
+		t.Error("no member did day 8")
+	}
+}
@@ -0,0 +1,126 @@
+package leaderboard
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"sync"
+)
+
+// MergedSource combines the latest events of several leaderboards of the
+// same year into one, so they can be ranked as a single leaderboard.
+type MergedSource struct {
+	Boards []*LeaderBoard
+
+	mu sync.Mutex
+	last []byte
+}
+
+func (s *MergedSource) Fetch(year int64, id int64) ([]byte, error) {
+	var events []*Event
+	for _, board := range s.Boards {
+		if event := board.Snapshot().Event; event != nil {
+			events = append(events, event)
+		}
+	}
+	if len(events) == 0 {
+		return nil, errors.New("none of the merged leaderboards has been fetched yet")
+	}
+
+	body, err := json.Marshal(MergeEvents(events...))
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if bytes.Equal(body, s.last) {
+		return nil, ErrNotModified
+	}
+	s.last = body
+
+	return body, nil
+}
+
+// RefreshAfterUpdate makes every update of l with new data request a
+// refresh of board, e.g. of a merged leaderboard including l.
+func (l *LeaderBoard) RefreshAfterUpdate(board *LeaderBoard) {
+	l.mu.Lock()
+	l.dependents = append(l.dependents, board)
+	l.mu.Unlock()
+}
+
+// MergeEvents combines events into one, as if all their members were on the
+// same leaderboard. A member of several leaderboards is counted once, with
+// the earliest time of every star, and the local scores are recalculated
+// among all members.
+func MergeEvents(events ...*Event) *Event {
+	merged := &Event{
+		Members: make(map[string]Member),
+	}
+
+	for _, event := range events {
+		if merged.Year == "" {
+			merged.Year = event.Year
+			merged.Day1Ts = event.Day1Ts
+			merged.NumDays = event.NumDays
+		}
+		if event.SchemaVersion > merged.SchemaVersion {
+			merged.SchemaVersion = event.SchemaVersion
+		}
+
+		// Members are keyed by their id, so the same member has the same
+		// key on every leaderboard.
+		for key, member := range event.Members {
+			m, ok := merged.Members[key]
+			if !ok {
+				m = member
+				m.CompletionDayLevels = make(map[int]map[int]Star)
+			}
+			if m.Name == "" {
+				m.Name = member.Name
+			}
+			if m.Id == 0 {
+				m.Id = member.Id
+			}
+			if member.GlobalScore > m.GlobalScore {
+				m.GlobalScore = member.GlobalScore
+			}
+
+			for day, parts := range member.CompletionDayLevels {
+				if _, ok := m.CompletionDayLevels[day]; !ok {
+					m.CompletionDayLevels[day] = make(map[int]Star)
+				}
+				for part, star := range parts {
+					if s, ok := m.CompletionDayLevels[day][part]; !ok || star.GetStarTs < s.GetStarTs {
+						m.CompletionDayLevels[day][part] = star
+					}
+				}
+			}
+
+			merged.Members[key] = m
+		}
+	}
+
+	for key, member := range merged.Members {
+		member.Stars = 0
+		member.LastStarTs = 0
+		for _, parts := range member.CompletionDayLevels {
+			for _, star := range parts {
+				member.Stars++
+				if star.GetStarTs > member.LastStarTs {
+					member.LastStarTs = star.GetStarTs
+				}
+			}
+		}
+		merged.Members[key] = member
+	}
+
+	for key, score := range merged.localScores() {
+		member := merged.Members[key]
+		member.LocalScore = score
+		merged.Members[key] = member
+	}
+
+	return merged
+}
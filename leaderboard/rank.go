@@ -0,0 +1,119 @@
+package leaderboard
+
+import (
+	"github.com/tlj/aoc-leaderboard-go/member_score"
+	"sort"
+	"time"
+)
+
+// rankDays sets the rank of every member for each part of each day, by the
+// time it took them. Members with the same time share a rank.
+func rankDays(days map[int]*Day) {
+	for _, day := range days {
+		var scores []*member_score.MemberScore
+		for _, ms := range day.MemberScores {
+			scores = append(scores, ms)
+		}
+
+		rankBy(scores, func(ms *member_score.MemberScore) int64 { return ms.Part1 }, func(ms *member_score.MemberScore, rank int) {
+			ms.Part1Rank = rank
+		})
+		rankBy(scores, func(ms *member_score.MemberScore) int64 { return ms.Part2 }, func(ms *member_score.MemberScore, rank int) {
+			ms.Part2Rank = rank
+		})
+	}
+}
+
+// rankBy ranks the scores with a non-zero time by that time, lowest first.
+func rankBy(scores []*member_score.MemberScore, time func(*member_score.MemberScore) int64, set func(*member_score.MemberScore, int)) {
+	var done []*member_score.MemberScore
+	for _, ms := range scores {
+		if time(ms) > 0 {
+			done = append(done, ms)
+		}
+	}
+	sort.Slice(done, func(i, j int) bool { return time(done[i]) < time(done[j]) })
+
+	rank := 0
+	for i, ms := range done {
+		if i == 0 || time(done[i-1]) != time(ms) {
+			rank = i + 1
+		}
+		set(ms, rank)
+	}
+}
+
+// rankTotals ranks the running totals after each day by rules, and sets
+// the Rank, RankHistory, RankChange and Streaks of the final totals. A
+// member is ranked from the first day that counts. The running totals get
+// the rating after each day from the RatingHistory set by rateDays.
+// Excluded days change neither the totals nor the streaks. The last day
+// doesn't break a streak until it has been unlocked for 24 hours at now,
+// as the member may still be on it.
+func rankTotals(year int64, days map[int]*Day, totals map[int]*member_score.MemberScore, maxDay int, rules Rules, now time.Time) {
+	running := make(map[int]*member_score.MemberScore)
+	for id, total := range totals {
+		total.RankHistory = make([]int, maxDay)
+		running[id] = &member_score.MemberScore{
+			Id: total.Id,
+			Name: total.Name,
+			AocLocalScore: total.AocLocalScore,
+			AocGlobalScore: total.AocGlobalScore,
+		}
+	}
+
+	for d := 1; d <= maxDay; d++ {
+		day, ok := days[d]
+		if ok && !day.Excluded {
+			for id, ms := range day.MemberScores {
+				r, ok := running[id]
+				if !ok {
+					continue
+				}
+				r.Points += ms.Points
+				r.Stars += ms.Stars
+				r.Add(ms, rules.Partial)
+			}
+		}
+
+		inProgress := now.Unix()-(Day{Year: year, Day: d}).DayStartsAt() < 24*60*60
+		for id, r := range running {
+			r.Rating = totals[id].RatingHistory[d-1]
+
+			var ms *member_score.MemberScore
+			if ok {
+				ms = day.MemberScores[id]
+			}
+			if rules.Excluded[d] || (d == maxDay && inProgress && (ms == nil || ms.Part2 == 0)) {
+				continue
+			}
+			r.Streaks.Next(ms)
+		}
+
+		var ranked []*member_score.MemberScore
+		for _, r := range running {
+			if r.Count > 0 {
+				r.UpdateStats()
+				ranked = append(ranked, r)
+			}
+		}
+		// Rank from a stable order, so ties don't change places at random.
+		sort.Slice(ranked, func(i, j int) bool { return ranked[i].Id < ranked[j].Id })
+		rules.Scoring.Rank(ranked)
+
+		for i, r := range ranked {
+			totals[r.Id].RankHistory[d-1] = i + 1
+		}
+	}
+
+	for _, total := range totals {
+		if maxDay == 0 {
+			continue
+		}
+		total.Streaks = running[total.Id].Streaks
+		total.Rank = total.RankHistory[maxDay-1]
+		if maxDay > 1 && total.RankHistory[maxDay-2] > 0 {
+			total.RankChange = total.RankHistory[maxDay-2] - total.Rank
+		}
+	}
+}
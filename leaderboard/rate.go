@@ -0,0 +1,105 @@
+package leaderboard
+
+import (
+	"github.com/tlj/aoc-leaderboard-go/member_score"
+	"github.com/tlj/aoc-leaderboard-go/rating"
+	"math"
+	"sort"
+	"time"
+)
+
+// rateDays rates the members by treating each day as a match, finished in
+// order of part 2 time, and then part 1 time for those with only part 1
+// done. Once a member has a star, a day they skip counts as a loss to
+// everyone who played it, except on the last day until it has been
+// unlocked for 24 hours at now, as they may still be on it. Excluded days
+// aren't played. It sets the Rating, RatingDeviation, RatingHistory and
+// RatingChange of the totals.
+func rateDays(year int64, days map[int]*Day, totals map[int]*member_score.MemberScore, maxDay int, now time.Time) {
+	engine := rating.NewEngine()
+	started := make(map[int]bool)
+
+	for _, total := range totals {
+		total.RatingHistory = make([]int, maxDay)
+	}
+
+	for d := 1; d <= maxDay; d++ {
+		if day, ok := days[d]; ok && !day.Excluded {
+			inProgress := d == maxDay && now.Unix()-day.DayStartsAt() < 24*60*60
+			play(engine, day, started, inProgress)
+		}
+		for id, total := range totals {
+			if r, ok := engine.Rating(id); ok {
+				total.RatingHistory[d-1] = int(math.Round(r.Value))
+			}
+		}
+	}
+
+	for id, total := range totals {
+		r, ok := engine.Rating(id)
+		if !ok || maxDay == 0 {
+			continue
+		}
+		total.Rating = int(math.Round(r.Value))
+		total.RatingDeviation = int(math.Round(r.Deviation))
+		if maxDay > 1 && total.RatingHistory[maxDay-2] > 0 {
+			total.RatingChange = total.Rating - total.RatingHistory[maxDay-2]
+		}
+	}
+}
+
+// play rates day as a match, and marks the members who played it as
+// started. The started members who skipped the day lose to everyone who
+// played it, unless the day is still in progress.
+func play(engine *rating.Engine, day *Day, started map[int]bool, inProgress bool) {
+	var played []*member_score.MemberScore
+	for _, ms := range day.MemberScores {
+		played = append(played, ms)
+	}
+	sort.Slice(played, func(i, j int) bool {
+		if finishedBefore(played[i], played[j]) {
+			return true
+		}
+		if finishedBefore(played[j], played[i]) {
+			return false
+		}
+		return played[i].Id < played[j].Id
+	})
+
+	var results []rating.Result
+	for i, ms := range played {
+		place := i + 1
+		if i > 0 && !finishedBefore(played[i-1], ms) {
+			place = results[i-1].Place
+		}
+		results = append(results, rating.Result{Id: ms.Id, Place: place})
+	}
+
+	var absent []int
+	for id := range started {
+		if _, ok := day.MemberScores[id]; !ok && !inProgress {
+			absent = append(absent, id)
+		}
+	}
+	sort.Ints(absent)
+	for _, id := range absent {
+		results = append(results, rating.Result{Id: id})
+	}
+
+	engine.Play(results)
+
+	for _, ms := range played {
+		started[ms.Id] = true
+	}
+}
+
+// finishedBefore reports whether a finished a day ahead of b.
+func finishedBefore(a *member_score.MemberScore, b *member_score.MemberScore) bool {
+	if (a.Part2 > 0) != (b.Part2 > 0) {
+		return a.Part2 > 0
+	}
+	if a.Part2 != b.Part2 {
+		return a.Part2 < b.Part2
+	}
+	return a.Part1 < b.Part1
+}
@@ -0,0 +1,122 @@
+package leaderboard
+
+import (
+	"sort"
+	"sync"
+)
+
+// Boards holds every leaderboard served by the process.
+var Boards = NewRegistry()
+
+type boardKey struct {
+	key string
+	year int64
+}
+
+// A Registry holds leaderboards keyed by their Key and year.
+type Registry struct {
+	mu sync.RWMutex
+	boards map[boardKey]*LeaderBoard
+	keys []string
+	years map[string][]int64
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		boards: make(map[boardKey]*LeaderBoard),
+		years: make(map[string][]int64),
+	}
+}
+
+// Add registers a leaderboard, replacing any board with the same key and
+// year.
+func (r *Registry) Add(l *LeaderBoard) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := boardKey{l.Key(), l.Year}
+	if _, ok := r.boards[key]; !ok {
+		if _, ok := r.years[key.key]; !ok {
+			r.keys = append(r.keys, key.key)
+		}
+		years := append(r.years[key.key], l.Year)
+		sort.Slice(years, func(i, j int) bool { return years[i] > years[j] })
+		r.years[key.key] = years
+	}
+	r.boards[key] = l
+}
+
+func (r *Registry) Get(key string, year int64) (*LeaderBoard, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	l, ok := r.boards[boardKey{key, year}]
+	return l, ok
+}
+
+// Latest returns the leaderboard for the most recent year of key.
+func (r *Registry) Latest(key string) (*LeaderBoard, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	years, ok := r.years[key]
+	if !ok {
+		return nil, false
+	}
+	return r.boards[boardKey{key, years[0]}], true
+}
+
+// Years returns the years registered for key, most recent first.
+func (r *Registry) Years(key string) []int64 {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return append([]int64(nil), r.years[key]...)
+}
+
+// Default returns the latest year of the first registered leaderboard,
+// which is served on the routes without a board id.
+func (r *Registry) Default() *LeaderBoard {
+	r.mu.RLock()
+	if len(r.keys) == 0 {
+		r.mu.RUnlock()
+		return nil
+	}
+	key := r.keys[0]
+	r.mu.RUnlock()
+
+	l, _ := r.Latest(key)
+	return l
+}
+
+// All returns the latest year of every leaderboard, sorted by name.
+func (r *Registry) All() []*LeaderBoard {
+	r.mu.RLock()
+	keys := append([]string(nil), r.keys...)
+	r.mu.RUnlock()
+
+	var boards []*LeaderBoard
+	for _, key := range keys {
+		l, _ := r.Latest(key)
+		boards = append(boards, l)
+	}
+	sort.Slice(boards, func(i, j int) bool { return boards[i].Name < boards[j].Name })
+	return boards
+}
+
+// Len returns the number of leaderboards, not counting their years.
+func (r *Registry) Len() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.keys)
+}
+
+// AllYears returns every year of every leaderboard, sorted by name and
+// then by year, most recent first.
+func (r *Registry) AllYears() []*LeaderBoard {
+	var boards []*LeaderBoard
+	for _, latest := range r.All() {
+		for _, year := range r.Years(latest.Key()) {
+			l, _ := r.Get(latest.Key(), year)
+			boards = append(boards, l)
+		}
+	}
+	return boards
+}
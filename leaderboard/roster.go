@@ -0,0 +1,88 @@
+package leaderboard
+
+import (
+	"log"
+	"sort"
+	"time"
+)
+
+// Kinds of RosterChange.
+const (
+	RosterJoined = "joined"
+	RosterLeft = "left"
+	RosterRenamed = "renamed"
+)
+
+// A RosterChange is a member joining, leaving or renaming themselves,
+// noticed when the leaderboard was fetched at At. OldName is empty for a
+// member who was anonymous before.
+type RosterChange struct {
+	At time.Time `json:"at"`
+	Kind string `json:"kind"`
+	MemberId int `json:"member_id"`
+	Name string `json:"name"`
+	OldName string `json:"old_name,omitempty"`
+}
+
+// DiffRoster returns the changes to the members between two consecutive
+// fetches of a leaderboard, ordered by kind and name.
+func DiffRoster(previous *Event, current *Event, at time.Time) []RosterChange {
+	var changes []RosterChange
+
+	for key, member := range current.Members {
+		before, ok := previous.Members[key]
+		switch {
+		case !ok:
+			changes = append(changes, RosterChange{At: at, Kind: RosterJoined, MemberId: member.Id, Name: member.Name})
+		case before.Name != member.Name:
+			changes = append(changes, RosterChange{At: at, Kind: RosterRenamed, MemberId: member.Id, Name: member.Name, OldName: before.Name})
+		}
+	}
+	for key, member := range previous.Members {
+		if _, ok := current.Members[key]; !ok {
+			changes = append(changes, RosterChange{At: at, Kind: RosterLeft, MemberId: member.Id, Name: member.Name})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].Kind != changes[j].Kind {
+			return changes[i].Kind < changes[j].Kind
+		}
+		return changes[i].Name < changes[j].Name
+	})
+
+	return changes
+}
+
+// Roster returns the roster changes recorded for the leaderboard, oldest
+// first.
+func (l *LeaderBoard) Roster() []RosterChange {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return append([]RosterChange(nil), l.roster...)
+}
+
+// recordRoster records the roster changes from the current event to event,
+// keeping them in the Store if there is one. Nothing is recorded for the
+// first event, since there is nothing to compare it with.
+func (l *LeaderBoard) recordRoster(event *Event, at time.Time) {
+	previous := l.Snapshot().Event
+	if previous == nil {
+		return
+	}
+
+	changes := DiffRoster(previous, event, at)
+	if len(changes) == 0 {
+		return
+	}
+
+	l.mu.Lock()
+	l.roster = append(l.roster, changes...)
+	l.mu.Unlock()
+
+	if l.Store != nil {
+		if err := l.Store.AppendRoster(l.Year, l.Id, changes); err != nil {
+			log.Printf("Error storing roster of leaderboard %d year %d: %v\n", l.Id, l.Year, err)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package leaderboard
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestDiffRoster(t *testing.T) {
+	at := time.Date(2018, 12, 3, 12, 0, 0, 0, time.UTC)
+	members := func(names map[int]string) *Event {
+		event := &Event{Members: make(map[string]Member)}
+		for id, name := range names {
+			event.Members[strconv.Itoa(id)] = Member{Id: id, Name: name}
+		}
+		return event
+	}
+
+	tests := []struct {
+		name string
+		previous map[int]string
+		current map[int]string
+		want []RosterChange
+	}{
+		{
+			name: "unchanged",
+			previous: map[int]string{1: "Ann", 2: "Bob"},
+			current: map[int]string{1: "Ann", 2: "Bob"},
+		},
+		{
+			name: "joined and left",
+			previous: map[int]string{1: "Ann", 2: "Bob"},
+			current: map[int]string{1: "Ann", 3: "Cid", 4: "Ada"},
+			want: []RosterChange{
+				{At: at, Kind: RosterJoined, MemberId: 4, Name: "Ada"},
+				{At: at, Kind: RosterJoined, MemberId: 3, Name: "Cid"},
+				{At: at, Kind: RosterLeft, MemberId: 2, Name: "Bob"},
+			},
+		},
+		{
+			name: "renamed",
+			previous: map[int]string{1: "Ann", 2: ""},
+			current: map[int]string{1: "Anne", 2: "Bob"},
+			want: []RosterChange{
+				{At: at, Kind: RosterRenamed, MemberId: 1, Name: "Anne", OldName: "Ann"},
+				{At: at, Kind: RosterRenamed, MemberId: 2, Name: "Bob"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		got := DiffRoster(members(test.previous), members(test.current), at)
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: got %+v, want %+v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestRecordRoster(t *testing.T) {
+	board := NewLeaderBoard(2018, 1, "Fixture", FixtureSource{})
+	event := loadFixture(t)
+
+	board.recordRoster(event, time.Now())
+	board.UpdateScores(event, time.Now())
+	if n := len(board.Roster()); n != 0 {
+		t.Errorf("first event recorded %d changes, want none", n)
+	}
+
+	renamed := *event
+	renamed.Members = make(map[string]Member)
+	for key, member := range event.Members {
+		if member.Id == 116603 {
+			member.Name = "Thomas"
+		}
+		renamed.Members[key] = member
+	}
+	board.recordRoster(&renamed, time.Now())
+
+	roster := board.Roster()
+	if len(roster) != 1 || roster[0].Kind != RosterRenamed || roster[0].Name != "Thomas" {
+		t.Errorf("got roster %+v, want member 116603 renamed to Thomas", roster)
+	}
+}
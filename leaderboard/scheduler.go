@@ -0,0 +1,171 @@
+package leaderboard
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// AocMinInterval is how often AoC asks private leaderboards to be polled
+// at most.
+const AocMinInterval = 15 * time.Minute
+
+// MaxBackoff is the longest a leaderboard with a Floor waits after failed
+// updates, unless its schedule says to wait longer anyway.
+const MaxBackoff = 2 * time.Hour
+
+// ErrRefreshTooSoon is returned when a refresh is requested sooner after
+// the previous fetch than the schedule allows.
+var ErrRefreshTooSoon = errors.New("leaderboard was updated too recently")
+
+// A Schedule decides how long to wait between updates of a leaderboard.
+type Schedule struct {
+	// Interval is the time between updates during the event.
+	Interval time.Duration
+	// Floor is the shortest interval allowed, whatever Interval says. It
+	// also limits how often a refresh can be requested.
+	Floor time.Duration
+	// OffSeasonInterval is used instead of Interval outside December
+	// 1st to 25th, if it is longer.
+	OffSeasonInterval time.Duration
+	// Jitter is the maximum random time added to every interval, so
+	// leaderboards started together don't keep polling at the same time.
+	Jitter time.Duration
+}
+
+// InSeason reports whether t is between the unlock of the first and the
+// last day of the event.
+func InSeason(year int64, t time.Time) bool {
+	start := time.Unix(Day{Year: year, Day: 1}.DayStartsAt(), 0)
+	end := time.Unix(Day{Year: year, Day: 26}.DayStartsAt(), 0)
+	return !t.Before(start) && t.Before(end)
+}
+
+// Next returns the time to wait before the next update of a leaderboard
+// for year.
+func (s Schedule) Next(year int64, now time.Time) time.Duration {
+	interval := s.Interval
+	if !InSeason(year, now) && s.OffSeasonInterval > interval {
+		interval = s.OffSeasonInterval
+	}
+	if interval < s.Floor {
+		interval = s.Floor
+	}
+	if s.Jitter > 0 {
+		interval += time.Duration(rand.Int63n(int64(s.Jitter)))
+	}
+	return interval
+}
+
+// Retry returns the time to wait before the next update of a leaderboard
+// for year after failures consecutive failed updates: Floor, doubled for
+// every failure up to MaxBackoff, but never shorter than Next.
+func (s Schedule) Retry(year int64, now time.Time, failures int) time.Duration {
+	interval := s.Next(year, now)
+	backoff := s.Floor
+	for i := 0; i < failures && backoff < MaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > MaxBackoff {
+		backoff = MaxBackoff
+	}
+	if backoff > interval {
+		return backoff
+	}
+	return interval
+}
+
+// A Limiter spaces out the requests of all leaderboards sharing it.
+type Limiter struct {
+	Spacing time.Duration
+
+	mu sync.Mutex
+	next time.Time
+}
+
+// Wait blocks until at least Spacing has passed since the previous caller
+// was let through.
+func (l *Limiter) Wait() {
+	l.mu.Lock()
+	now := time.Now()
+	wait := time.Duration(0)
+	if l.next.After(now) {
+		wait = l.next.Sub(now)
+	}
+	l.next = now.Add(wait + l.Spacing)
+	l.mu.Unlock()
+
+	time.Sleep(wait)
+}
+
+// Poll updates the leaderboard according to its Schedule until the process
+// exits, backing off while updates fail. Once an archived leaderboard has
+// been fetched it is only updated when a refresh is requested.
+func (l *LeaderBoard) Poll() {
+	failures := 0
+	for {
+		var wait <-chan time.Time
+		var timer *time.Timer
+		if l.Archived && !l.Status().LastSyncedAt.IsZero() {
+			l.setNextUpdateAt(time.Time{})
+		} else {
+			interval := l.Schedule.Next(l.Year, time.Now())
+			if failures > 0 {
+				interval = l.Schedule.Retry(l.Year, time.Now(), failures)
+			}
+			l.setNextUpdateAt(time.Now().Add(interval))
+			timer = time.NewTimer(interval)
+			wait = timer.C
+		}
+
+		select {
+		case <-wait:
+		case <-l.refresh:
+			log.Printf("Refresh of leaderboard %s year %d requested.", l.Key(), l.Year)
+			if timer != nil {
+				timer.Stop()
+			}
+		}
+
+		// Retrying sooner than Floor would break the limit it sets, so a
+		// failed update then waits for the Schedule's Retry instead.
+		attempts := 4
+		if l.Schedule.Floor > 0 {
+			attempts = 1
+		}
+		err := l.UpdateWithRetry(attempts, 5*time.Second, 60*time.Second)
+		if err != nil {
+			failures++
+			log.Printf("Error updating leaderboard %s year %d: %v\n", l.Key(), l.Year, err)
+		} else {
+			failures = 0
+		}
+	}
+}
+
+// RequestRefresh makes Poll update the leaderboard right away, unless the
+// previous fetch was less than the Schedule's Floor ago.
+func (l *LeaderBoard) RequestRefresh() error {
+	l.mu.RLock()
+	since := time.Since(l.lastAttemptAt)
+	l.mu.RUnlock()
+
+	if since < l.Schedule.Floor {
+		return fmt.Errorf("%v, try again in %s", ErrRefreshTooSoon, (l.Schedule.Floor - since).Round(time.Second))
+	}
+
+	select {
+	case l.refresh <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+func (l *LeaderBoard) setNextUpdateAt(t time.Time) {
+	l.mu.Lock()
+	l.nextUpdateAt = t
+	l.mu.Unlock()
+}
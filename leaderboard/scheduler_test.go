@@ -0,0 +1,32 @@
+package leaderboard
+
+import (
+	"testing"
+	"time"
+)
+
+func TestScheduleRetry(t *testing.T) {
+	inSeason := time.Date(2018, 12, 10, 12, 0, 0, 0, time.UTC)
+	offSeason := time.Date(2019, 6, 1, 12, 0, 0, 0, time.UTC)
+	aoc := Schedule{Interval: AocMinInterval, Floor: AocMinInterval, OffSeasonInterval: 6 * time.Hour}
+
+	tests := []struct {
+		schedule Schedule
+		now time.Time
+		failures int
+		want time.Duration
+	}{
+		{aoc, inSeason, 0, 15 * time.Minute},
+		{aoc, inSeason, 1, 30 * time.Minute},
+		{aoc, inSeason, 2, time.Hour},
+		{aoc, inSeason, 3, 2 * time.Hour},
+		{aoc, inSeason, 10, MaxBackoff},
+		{aoc, offSeason, 3, 6 * time.Hour},
+		{Schedule{Interval: 10 * time.Second}, inSeason, 5, 10 * time.Second},
+	}
+	for _, test := range tests {
+		if got := test.schedule.Retry(2018, test.now, test.failures); got != test.want {
+			t.Errorf("Retry(%v, %d) = %v, want %v", test.now, test.failures, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package leaderboard
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// A Source provides the raw JSON payload of a private leaderboard.
+type Source interface {
+	Fetch(year int64, id int64) ([]byte, error)
+}
+
+// ErrNotModified is returned by a Source when the leaderboard hasn't
+// changed since it was last fetched.
+var ErrNotModified = errors.New("leaderboard not modified")
+
+// An AuthError is returned when AoC doesn't accept the session cookie,
+// which it signals by redirecting or refusing the request.
+type AuthError struct {
+	StatusCode int
+	Location string
+}
+
+func (e *AuthError) Error() string {
+	if e.Location != "" {
+		return fmt.Sprintf("not logged in: status %d, redirected to %s", e.StatusCode, e.Location)
+	}
+	return fmt.Sprintf("not logged in: status %d", e.StatusCode)
+}
+
+// IsAuthError reports whether err means the session cookie was rejected.
+func IsAuthError(err error) bool {
+	_, ok := err.(*AuthError)
+	return ok
+}
+
+// AocBaseUrl is where the AoC API is served.
+const AocBaseUrl = "https://adventofcode.com"
+
+// AocSource fetches the leaderboard from the adventofcode.com API. It sends
+// conditional requests, so an unchanged leaderboard isn't downloaded again.
+type AocSource struct {
+	SessionCookie string
+	// BaseUrl replaces AocBaseUrl, e.g. to use a mock-aoc server.
+	BaseUrl string
+
+	mu sync.Mutex
+	validators map[string]validator
+}
+
+// validator holds the headers needed to make a conditional request.
+type validator struct {
+	etag string
+	lastModified string
+}
+
+func (s *AocSource) Fetch(year int64, id int64) ([]byte, error) {
+	baseUrl := s.BaseUrl
+	if baseUrl == "" {
+		baseUrl = AocBaseUrl
+	}
+	url := fmt.Sprintf("%s/%d/leaderboard/private/view/%d.json", strings.TrimSuffix(baseUrl, "/"), year, id)
+
+	log.Printf("Updating from %s.", url)
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.AddCookie(&http.Cookie{Name: "session", Value: s.SessionCookie})
+
+	s.mu.Lock()
+	v := s.validators[url]
+	s.mu.Unlock()
+	if v.etag != "" {
+		req.Header.Set("If-None-Match", v.etag)
+	}
+	if v.lastModified != "" {
+		req.Header.Set("If-Modified-Since", v.lastModified)
+	}
+
+	client := http.Client{
+		// AoC redirects to the leaderboard page when the cookie isn't
+		// valid, so redirects are not followed but reported.
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified {
+		return nil, ErrNotModified
+	}
+
+	switch {
+	case resp.StatusCode >= 300 && resp.StatusCode < 400,
+		resp.StatusCode == http.StatusUnauthorized,
+		resp.StatusCode == http.StatusForbidden:
+		return nil, &AuthError{StatusCode: resp.StatusCode, Location: resp.Header.Get("Location")}
+	case resp.StatusCode != 200:
+		return nil, fmt.Errorf("status: %d", resp.StatusCode)
+	}
+
+	if strings.HasPrefix(resp.Header.Get("Content-Type"), "text/html") {
+		return nil, &AuthError{StatusCode: resp.StatusCode}
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	if s.validators == nil {
+		s.validators = make(map[string]validator)
+	}
+	s.validators[url] = validator{
+		etag: resp.Header.Get("ETag"),
+		lastModified: resp.Header.Get("Last-Modified"),
+	}
+	s.mu.Unlock()
+
+	return body, nil
+}
+
+// FileSource reads the leaderboard from a local JSON file, e.g. a payload
+// recorded from the AoC API.
+type FileSource struct {
+	Path string
+}
+
+func (s FileSource) Fetch(year int64, id int64) ([]byte, error) {
+	log.Printf("Updating from file %s.", s.Path)
+	return ioutil.ReadFile(s.Path)
+}
+
+// DefaultFixture is a recorded 2018 leaderboard, relative to the working
+// directory the server runs in.
+const DefaultFixture = "leaderboard/testdata/fixture.json"
+
+// FixtureSource serves a leaderboard held in memory. Without Data it falls
+// back to the recorded leaderboard in DefaultFixture.
+type FixtureSource struct {
+	Data []byte
+}
+
+func (s FixtureSource) Fetch(year int64, id int64) ([]byte, error) {
+	log.Printf("Fake updating from fixture.")
+	if s.Data == nil {
+		return ioutil.ReadFile(DefaultFixture)
+	}
+	return s.Data, nil
+}
+
+// NewSource returns the Source for the given kind: "aoc", "file" or "fixture".
+// baseUrl is only used by the "aoc" source, and defaults to AocBaseUrl.
+func NewSource(kind string, sessionCookie string, path string, baseUrl string) (Source, error) {
+	switch kind {
+	case "aoc", "":
+		return &AocSource{SessionCookie: sessionCookie, BaseUrl: baseUrl}, nil
+	case "file":
+		if path == "" {
+			return nil, fmt.Errorf("file source requires a path")
+		}
+		return FileSource{Path: path}, nil
+	case "fixture":
+		return FixtureSource{}, nil
+	}
+	return nil, fmt.Errorf("unknown source %q", kind)
+}
@@ -0,0 +1,99 @@
+package leaderboard
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+var offsetPattern = regexp.MustCompile(`^([+-])([0-9]{2}):([0-9]{2})$`)
+
+// A StartRule tells when a member starts the puzzles, for measuring how
+// long they actually took instead of how long since the puzzle unlocked.
+type StartRule struct {
+	// Location is the time zone of the daily start at Hour:Minute. There is
+	// no daily start if it is nil.
+	Location *time.Location
+	Hour int
+	Minute int
+	// Days are the starts registered for single days, by day number,
+	// overriding the daily start.
+	Days map[int]time.Time
+}
+
+// NewStartRule parses a daily start at clock, e.g. "07:00", in timezone,
+// either a name like "America/Los_Angeles" or an offset like "-08:00", and
+// the starts of single days as RFC 3339 times. Timezone and clock are
+// empty for a rule of single days only.
+func NewStartRule(timezone string, clock string, days map[int]string) (StartRule, error) {
+	rule := StartRule{Days: make(map[int]time.Time)}
+
+	if clock != "" {
+		t, err := time.Parse("15:04", clock)
+		if err != nil {
+			return rule, fmt.Errorf("parsing start time %q: %v", clock, err)
+		}
+		rule.Hour, rule.Minute = t.Hour(), t.Minute()
+
+		rule.Location, err = parseLocation(timezone)
+		if err != nil {
+			return rule, err
+		}
+	}
+
+	for day, value := range days {
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return rule, fmt.Errorf("parsing start of day %d: %v", day, err)
+		}
+		rule.Days[day] = t
+	}
+
+	return rule, nil
+}
+
+func parseLocation(timezone string) (*time.Location, error) {
+	if timezone == "" {
+		return time.UTC, nil
+	}
+	if m := offsetPattern.FindStringSubmatch(timezone); m != nil {
+		hours, _ := strconv.Atoi(m[2])
+		minutes, _ := strconv.Atoi(m[3])
+		offset := hours*60*60 + minutes*60
+		if m[1] == "-" {
+			offset = -offset
+		}
+		return time.FixedZone(timezone, offset), nil
+	}
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, fmt.Errorf("parsing timezone %q: %v", timezone, err)
+	}
+	return location, nil
+}
+
+// startsAt returns when the member started day, and whether the rule
+// covers the day. A daily start is the first one after the puzzle
+// unlocked, and no start is earlier than the unlock.
+func (r StartRule) startsAt(d Day) (int64, bool) {
+	unlock := d.DayStartsAt()
+
+	if t, ok := r.Days[d.Day]; ok {
+		if t.Unix() < unlock {
+			return unlock, true
+		}
+		return t.Unix(), true
+	}
+
+	if r.Location == nil {
+		return unlock, false
+	}
+
+	u := time.Unix(unlock, 0).In(r.Location)
+	start := time.Date(u.Year(), u.Month(), u.Day(), r.Hour, r.Minute, 0, 0, r.Location)
+	if start.Before(u) {
+		start = time.Date(u.Year(), u.Month(), u.Day()+1, r.Hour, r.Minute, 0, 0, r.Location)
+	}
+	return start.Unix(), true
+}
@@ -0,0 +1,170 @@
+package leaderboard
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const storeTimeLayout = "20060102T150405Z"
+
+// A Store keeps the raw payloads fetched for each leaderboard on disk, as
+// Dir/{id}/{year}/{timestamp}.json.
+type Store struct {
+	Dir string
+	// Keep is the number of payloads kept per leaderboard and year. Zero
+	// keeps all of them.
+	Keep int
+	// MaxAge is how long payloads are kept. Zero keeps them forever. The
+	// newest payload is always kept.
+	MaxAge time.Duration
+}
+
+func (s *Store) dir(year int64, id int64) string {
+	return filepath.Join(s.Dir, fmt.Sprintf("%d", id), fmt.Sprintf("%d", year))
+}
+
+// files returns the payload files of a leaderboard, newest first.
+func (s *Store) files(year int64, id int64) ([]string, error) {
+	infos, err := ioutil.ReadDir(s.dir(year, id))
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, info := range infos {
+		if !info.IsDir() && strings.HasSuffix(info.Name(), ".json") {
+			names = append(names, info.Name())
+		}
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(names)))
+
+	return names, nil
+}
+
+// Save writes body as the newest payload of a leaderboard, unless it is
+// identical to the newest payload already stored, and prunes old payloads.
+func (s *Store) Save(year int64, id int64, at time.Time, body []byte) error {
+	latest, _, err := s.Latest(year, id)
+	if err == nil && bytes.Equal(latest, body) {
+		return nil
+	}
+
+	dir := s.dir(year, id)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	name := filepath.Join(dir, at.UTC().Format(storeTimeLayout)+".json")
+	if err := ioutil.WriteFile(name+".tmp", body, 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(name+".tmp", name); err != nil {
+		return err
+	}
+
+	return s.prune(year, id, at)
+}
+
+// Latest returns the newest payload of a leaderboard and when it was
+// fetched. The error satisfies os.IsNotExist if nothing is stored.
+func (s *Store) Latest(year int64, id int64) ([]byte, time.Time, error) {
+	names, err := s.files(year, id)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	if len(names) == 0 {
+		return nil, time.Time{}, os.ErrNotExist
+	}
+
+	at, err := time.Parse(storeTimeLayout, strings.TrimSuffix(names[0], ".json"))
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("unexpected file %s: %v", names[0], err)
+	}
+
+	body, err := ioutil.ReadFile(filepath.Join(s.dir(year, id), names[0]))
+	return body, at, err
+}
+
+// AppendRoster adds changes to the roster changes stored for a leaderboard,
+// kept as one JSON object per line in roster.jsonl next to its payloads.
+func (s *Store) AppendRoster(year int64, id int64, changes []RosterChange) error {
+	dir := s.dir(year, id)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(filepath.Join(dir, "roster.jsonl"), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(f)
+	for _, change := range changes {
+		if err := encoder.Encode(change); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	return f.Close()
+}
+
+// Roster returns the roster changes stored for a leaderboard, oldest first.
+func (s *Store) Roster(year int64, id int64) ([]RosterChange, error) {
+	f, err := os.Open(filepath.Join(s.dir(year, id), "roster.jsonl"))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var changes []RosterChange
+	decoder := json.NewDecoder(f)
+	for decoder.More() {
+		change := RosterChange{}
+		if err := decoder.Decode(&change); err != nil {
+			return changes, err
+		}
+		changes = append(changes, change)
+	}
+	return changes, nil
+}
+
+func (s *Store) prune(year int64, id int64, now time.Time) error {
+	names, err := s.files(year, id)
+	if err != nil {
+		return err
+	}
+
+	for i, name := range names {
+		if i == 0 {
+			continue
+		}
+
+		expired := false
+		if s.Keep > 0 && i >= s.Keep {
+			expired = true
+		}
+		if s.MaxAge > 0 {
+			at, err := time.Parse(storeTimeLayout, strings.TrimSuffix(name, ".json"))
+			if err == nil && now.Sub(at) > s.MaxAge {
+				expired = true
+			}
+		}
+
+		if expired {
+			log.Printf("Removing stored leaderboard %d year %d from %s.", id, year, name)
+			if err := os.Remove(filepath.Join(s.dir(year, id), name)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -1,15 +1,20 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	handlers2 "github.com/gorilla/handlers"
 	"github.com/gorilla/mux"
+	"github.com/tlj/aoc-leaderboard-go/config"
 	"github.com/tlj/aoc-leaderboard-go/handlers"
 	"github.com/tlj/aoc-leaderboard-go/leaderboard"
+	"github.com/tlj/aoc-leaderboard-go/member_score"
+	"github.com/tlj/aoc-leaderboard-go/mock_aoc"
 	"log"
 	"net/http"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -31,44 +36,396 @@ func getEnvNumeric(key string, fallback int64) int64 {
 	return fallback
 }
 
-func main() {
+// boardRoutes registers the pages of a single leaderboard on r.
+func boardRoutes(r *mux.Router) {
+	r.HandleFunc("/day/{day:[0-9]+}/{orderBy}", handlers.Day)
+	r.HandleFunc("/day/{day:[0-9]+}", handlers.Day)
+	r.HandleFunc("/day/{day:[0-9]+}/", handlers.Day)
+	r.HandleFunc("/day", handlers.Day)
+	r.HandleFunc("/embed", handlers.Embed)
+	r.HandleFunc("/topscores", handlers.TopScores)
+	r.HandleFunc("/replay", handlers.Replay)
+	r.HandleFunc("/replay/events", handlers.ReplayEvents)
+	r.HandleFunc("/replay/frame", handlers.ReplayFrame)
+	r.HandleFunc("/ranks.json", handlers.Ranks)
+	r.HandleFunc("/ratings.json", handlers.Ratings)
+	r.HandleFunc("/members", handlers.Members)
+	r.HandleFunc("/members/feed", handlers.MembersFeed)
+	r.HandleFunc("/member/{member:[0-9]+}", handlers.Member)
+	r.HandleFunc("/teams", handlers.Teams)
+	r.HandleFunc("/teams/{team}", handlers.Team)
+	r.HandleFunc("/refresh", handlers.Refresh).Methods("POST")
+	r.HandleFunc("/", handlers.Day)
+}
+
+// addBoard registers board, serving its newest stored payload if there
+// is one, and returns a function that starts polling it.
+func addBoard(board *leaderboard.LeaderBoard) func() {
+	loaded := false
+	if err := board.LoadFromStore(); err == nil {
+		log.Printf("Loaded stored leaderboard %s year %d.", board.Key(), board.Year)
+		loaded = true
+	} else if !os.IsNotExist(err) {
+		log.Printf("Error loading stored leaderboard %s year %d: %v\n", board.Key(), board.Year, err)
+	}
+
+	leaderboard.Boards.Add(board)
+
+	// Don't fetch a stored leaderboard again sooner than the schedule
+	// allows, so restarts don't poll AoC more often than it asks for.
+	fresh := loaded && time.Since(board.Status().LastSyncedAt) < board.Schedule.Floor
+
+	return func() {
+		go func() {
+			if !loaded || (!board.Archived && !fresh) {
+				if err := board.UpdateFromSource(); err != nil {
+					log.Printf("Error updating leaderboard %s year %d: %v\n", board.Key(), board.Year, err)
+				}
+			}
+			board.Poll()
+		}()
+	}
+}
+
+// mergedSchedule updates a merged leaderboard as often as the most often
+// updated of boards. They also refresh it whenever they are updated, so
+// this only matters if it failed. Merging only reads the other
+// leaderboards, so it isn't limited by AoC.
+func mergedSchedule(c *config.Config, boards []int64) leaderboard.Schedule {
+	var schedule leaderboard.Schedule
+	for _, b := range c.Boards {
+		for _, id := range boards {
+			if b.Id != id {
+				continue
+			}
+			interval := time.Duration(b.PollInterval) * time.Second
+			if schedule.Interval == 0 || interval < schedule.Interval {
+				schedule.Interval = interval
+			}
+			offSeasonInterval := time.Duration(b.OffSeasonInterval) * time.Second
+			if schedule.OffSeasonInterval == 0 || offSeasonInterval < schedule.OffSeasonInterval {
+				schedule.OffSeasonInterval = offSeasonInterval
+			}
+		}
+	}
+	return schedule
+}
+
+func getEnvIds(key string) []int64 {
+	var ids []int64
+	for _, value := range strings.Split(getEnv(key, ""), ",") {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		v, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			log.Fatalf("Error getting env %s as list of ids: %v\n", key, err)
+		}
+		ids = append(ids, v)
+	}
+	return ids
+}
+
+// getEnvDays parses a list of year:day pairs, e.g. "2018:6,2020:1", as
+// the days of each year.
+func getEnvDays(key string) map[int64][]int {
+	days := make(map[int64][]int)
+	for _, value := range strings.Split(getEnv(key, ""), ",") {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		parts := strings.Split(value, ":")
+		if len(parts) != 2 {
+			log.Fatalf("Error getting env %s as list of year:day: %q\n", key, value)
+		}
+		year, err := strconv.ParseInt(parts[0], 10, 64)
+		if err != nil {
+			log.Fatalf("Error getting env %s as list of year:day: %v\n", key, err)
+		}
+		day, err := strconv.Atoi(parts[1])
+		if err != nil {
+			log.Fatalf("Error getting env %s as list of year:day: %v\n", key, err)
+		}
+		days[year] = append(days[year], day)
+	}
+	return days
+}
+
+func loadConfig() *config.Config {
 	cookie := getEnv("AOC_SESSION_COOKIE", "")
 	year := getEnvNumeric("AOC_YEAR", int64(time.Now().Year()))
-	id := getEnvNumeric("AOC_LEADERBOARD_ID", 0)
+	firstYear := getEnvNumeric("AOC_FIRST_YEAR", 2015)
 	debug := getEnvNumeric("AOC_DEBUG", 0)
-	port := getEnvNumeric("HTTP_PORT", 8080)
+	pollInterval := getEnvNumeric("AOC_POLL_INTERVAL", 900)
+	offSeasonInterval := getEnvNumeric("AOC_OFF_SEASON_INTERVAL", 6*60*60)
+	jitter := getEnvNumeric("AOC_POLL_JITTER", 60)
+	requestSpacing := getEnvNumeric("AOC_REQUEST_SPACING", 2)
+	alertWebhook := getEnv("AOC_ALERT_WEBHOOK", "")
+	staleAlertAfter := getEnvNumeric("AOC_STALE_ALERT_AFTER", 60*60)
+	dataDir := getEnv("AOC_DATA_DIR", "")
+	dataKeep := getEnvNumeric("AOC_DATA_KEEP", 100)
+	dataMaxAge := getEnvNumeric("AOC_DATA_MAX_AGE_DAYS", 0)
+	sourceKind := getEnv("AOC_SOURCE", "aoc")
+	sourceFile := getEnv("AOC_SOURCE_FILE", "")
+	baseUrl := getEnv("AOC_BASE_URL", "")
+	scoring := getEnv("AOC_SCORING", member_score.DefaultStrategy.Name())
+	partialDays := getEnv("AOC_PARTIAL_DAYS", member_score.PartialComplete)
+	partialPenalty := getEnvNumeric("AOC_PARTIAL_PENALTY", 24*60*60)
 
-	if cookie == "" || id == 0 {
+	if debug == 1 {
+		sourceKind = "fixture"
+	}
+
+	c := &config.Config{}
+	if path := getEnv("AOC_CONFIG", ""); path != "" {
+		var err error
+		c, err = config.Load(path)
+		if err != nil {
+			log.Fatalf("Error loading config: %v\n", err)
+		}
+	} else {
+		for _, id := range getEnvIds("AOC_LEADERBOARD_ID") {
+			c.Boards = append(c.Boards, config.Board{Id: id})
+		}
+	}
+	c.Defaults(config.Config{
+		Year: year,
+		FirstYear: firstYear,
+		DataDir: dataDir,
+		DataKeep: int(dataKeep),
+		DataMaxAgeDays: dataMaxAge,
+		RequestSpacing: requestSpacing,
+		AlertWebhook: alertWebhook,
+		StaleAlertAfter: staleAlertAfter,
+		ExcludedDays: getEnvDays("AOC_EXCLUDED_DAYS"),
+		TeamScoring: getEnv("AOC_TEAM_SCORING", member_score.TeamBest),
+		TeamBest: int(getEnvNumeric("AOC_TEAM_BEST", 3)),
+	}, config.Board{
+		SessionCookie: cookie,
+		Source: sourceKind,
+		SourceFile: sourceFile,
+		BaseUrl: baseUrl,
+		Scoring: scoring,
+		PartialDays: partialDays,
+		PartialPenalty: partialPenalty,
+		PollInterval: pollInterval,
+		OffSeasonInterval: offSeasonInterval,
+		Jitter: jitter,
+	})
+
+	if len(c.Boards) == 0 {
 		log.Fatal("AOC_SESSION_COOKIE and AOC_LEADERBOARD_ID env variables required.")
 	}
+	for _, b := range c.Boards {
+		if b.Source == "aoc" && b.SessionCookie == "" {
+			log.Fatalf("No session cookie for leaderboard %d.", b.Id)
+		}
+		if _, ok := member_score.LookupStrategy(b.Scoring); !ok {
+			log.Fatalf("Unknown scoring %q for leaderboard %d.", b.Scoring, b.Id)
+		}
+		if !member_score.ValidPartialPolicy(b.PartialDays) {
+			log.Fatalf("Unknown partial days policy %q for leaderboard %d.", b.PartialDays, b.Id)
+		}
+	}
+	for _, m := range c.Merged {
+		if _, ok := member_score.LookupStrategy(m.Scoring); !ok {
+			log.Fatalf("Unknown scoring %q for merged leaderboard %s.", m.Scoring, m.Slug)
+		}
+		if !member_score.ValidPartialPolicy(m.PartialDays) {
+			log.Fatalf("Unknown partial days policy %q for merged leaderboard %s.", m.PartialDays, m.Slug)
+		}
+	}
 
-	log.Printf("Starting leaderboard %d year %d.", id, year)
+	if !member_score.ValidTeamAggregation(c.TeamScoring) {
+		log.Fatalf("Unknown team scoring %q.", c.TeamScoring)
+	}
 
-	leaderboard.CurrentBoard = leaderboard.LeaderBoard{
-		SessionCookie: cookie,
-		Year: year,
-		Id: id,
-		Debug: debug == 1,
+	return c
+}
+
+// teams returns the teams in c.
+func teams(c *config.Config) []member_score.Team {
+	var teams []member_score.Team
+	for _, t := range c.Teams {
+		teams = append(teams, member_score.Team{Name: t.Name, Members: t.Members})
+	}
+	return teams
+}
+
+// startRules parses the personal starts in c by member id.
+func startRules(c *config.Config) map[int]leaderboard.StartRule {
+	rules := make(map[int]leaderboard.StartRule)
+	for _, s := range c.Starts {
+		rule, err := leaderboard.NewStartRule(s.Timezone, s.Time, s.Days)
+		if err != nil {
+			log.Fatalf("Error in start of member %d: %v", s.MemberId, err)
+		}
+		rules[s.MemberId] = rule
+	}
+	return rules
+}
+
+// mockAoc runs a server imitating the AoC leaderboard API, which the aoc
+// source can be pointed at with AOC_BASE_URL.
+func mockAoc(args []string) {
+	flags := flag.NewFlagSet("mock-aoc", flag.ExitOnError)
+	port := flags.Int("port", 8081, "port to listen on")
+	cookie := flags.String("cookie", "", "only accept this session cookie; any cookie is accepted if empty")
+	dataDir := flags.String("data", "", "serve the newest leaderboards stored in this directory, as written by AOC_DATA_DIR")
+	file := flags.String("file", "", "serve this leaderboard JSON for every leaderboard not in -data; defaults to the 2018 fixture in "+leaderboard.DefaultFixture)
+	speed := flags.Float64("speed", 0, "replay the stars from December 1st at this many times real time; 0 serves all stars")
+	errorRate := flags.Float64("error-rate", 0, "fraction of requests answered with a 502")
+	redirectRate := flags.Float64("redirect-rate", 0, "fraction of requests redirected as if the cookie had expired")
+	slowRate := flags.Float64("slow-rate", 0, "fraction of requests answered after -delay")
+	delay := flags.Duration("delay", 10*time.Second, "delay of slow requests")
+	flags.Parse(args)
+
+	server := &mock_aoc.Server{
+		SessionCookie: *cookie,
+		Source: leaderboard.FixtureSource{},
+		Speed: *speed,
+		Start: time.Now(),
+		ErrorRate: *errorRate,
+		RedirectRate: *redirectRate,
+		SlowRate: *slowRate,
+		Delay: *delay,
+	}
+	if *dataDir != "" {
+		server.Store = &leaderboard.Store{Dir: *dataDir}
+	}
+	if *file != "" {
+		server.Source = leaderboard.FileSource{Path: *file}
+	}
+
+	log.Printf("Mock AoC listening to port %d.\n", *port)
+	log.Fatal(http.ListenAndServe(fmt.Sprintf(":%d", *port), handlers2.CombinedLoggingHandler(os.Stdout, server.Handler())))
+}
+
+func main() {
+	if len(os.Args) > 1 && os.Args[1] == "mock-aoc" {
+		mockAoc(os.Args[2:])
+		return
+	}
+
+	port := getEnvNumeric("HTTP_PORT", 8080)
+	c := loadConfig()
+
+	var store *leaderboard.Store
+	if c.DataDir != "" {
+		store = &leaderboard.Store{
+			Dir: c.DataDir,
+			Keep: c.DataKeep,
+			MaxAge: time.Duration(c.DataMaxAgeDays) * 24 * time.Hour,
+		}
+	}
+
+	limiter := &leaderboard.Limiter{Spacing: time.Duration(c.RequestSpacing) * time.Second}
+
+	var alerter leaderboard.Alerter = leaderboard.LogAlerter{}
+	if c.AlertWebhook != "" {
+		alerter = leaderboard.WebhookAlerter{Url: c.AlertWebhook}
 	}
-	leaderboard.CurrentBoard.UpdateFromSource()
 
-	go func() {
-		for range time.NewTicker(120 * time.Second).C {
-			leaderboard.CurrentBoard.UpdateFromSource()
+	starts := startRules(c)
+	teams := teams(c)
+	teamAggregation := member_score.TeamAggregation{Kind: c.TeamScoring, Best: c.TeamBest}
+
+	// The polls are only started once every board is added, so merged
+	// boards hear of every update of the boards they merge.
+	var polls []func()
+
+	for _, b := range c.Boards {
+		source, err := leaderboard.NewSource(b.Source, b.SessionCookie, b.SourceFile, b.BaseUrl)
+		if err != nil {
+			log.Fatalf("Error creating source for leaderboard %d: %v\n", b.Id, err)
+		}
+
+		log.Printf("Starting leaderboard %d year %d.", b.Id, c.Year)
+
+		schedule := leaderboard.Schedule{
+			Interval: time.Duration(b.PollInterval) * time.Second,
+			OffSeasonInterval: time.Duration(b.OffSeasonInterval) * time.Second,
+			Jitter: time.Duration(b.Jitter) * time.Second,
 		}
-	}()
+		// AoC's limit doesn't apply to a mock-aoc server.
+		if b.Source == "aoc" && b.BaseUrl == "" {
+			schedule.Floor = leaderboard.AocMinInterval
+		}
+
+		scoring, _ := member_score.LookupStrategy(b.Scoring)
+
+		for year := c.Year; year >= c.FirstYear; year-- {
+			board := leaderboard.NewLeaderBoard(year, b.Id, b.Name, source)
+			board.Scoring = scoring
+			board.Partial = member_score.PartialPolicy{Kind: b.PartialDays, Penalty: b.PartialPenalty}
+			board.Starts = starts
+			board.ExcludedDays = c.Excluded(year, b.ExcludedDays)
+			board.Teams = teams
+			board.TeamAggregation = teamAggregation
+			board.Archived = year < c.Year
+			board.Store = store
+			board.Schedule = schedule
+			board.Limiter = limiter
+			board.Alerter = alerter
+			board.StaleAlertAfter = time.Duration(c.StaleAlertAfter) * time.Second
+			polls = append(polls, addBoard(board))
+		}
+	}
+
+	for _, m := range c.Merged {
+		log.Printf("Starting merged leaderboard %s of %v.", m.Slug, m.Boards)
+
+		for year := c.Year; year >= c.FirstYear; year-- {
+			source := &leaderboard.MergedSource{}
+			for _, id := range m.Boards {
+				if board, ok := leaderboard.Boards.Get(strconv.FormatInt(id, 10), year); ok {
+					source.Boards = append(source.Boards, board)
+				}
+			}
+
+			board := leaderboard.NewLeaderBoard(year, 0, m.Name, source)
+			board.Slug = m.Slug
+			board.Scoring, _ = member_score.LookupStrategy(m.Scoring)
+			board.Partial = member_score.PartialPolicy{Kind: m.PartialDays, Penalty: m.PartialPenalty}
+			board.Starts = starts
+			board.ExcludedDays = c.Excluded(year, m.ExcludedDays)
+			board.Teams = teams
+			board.TeamAggregation = teamAggregation
+			board.Archived = year < c.Year
+			board.Schedule = mergedSchedule(c, m.Boards)
+			polls = append(polls, addBoard(board))
+
+			for _, b := range source.Boards {
+				b.RefreshAfterUpdate(board)
+			}
+		}
+	}
+
+	for _, poll := range polls {
+		poll()
+	}
 
 	r := mux.NewRouter()
 
 	cssHandler := http.FileServer(http.Dir("./css/"))
 	http.Handle("/css/", http.StripPrefix("/css/", cssHandler))
-	r.HandleFunc("/day/{day:[0-9]+}/{orderBy}", handlers.Day)
-	r.HandleFunc("/day/{day:[0-9]+}", handlers.Day)
-	r.HandleFunc("/day/{day:[0-9]+}/", handlers.Day)
-	r.HandleFunc("/day", handlers.Day)
-	r.HandleFunc("/embed", handlers.Embed)
-	r.HandleFunc("/topscores", handlers.TopScores)
-	r.HandleFunc("/", handlers.Day)
+	r.HandleFunc("/boards", handlers.Boards)
+	r.HandleFunc("/status", handlers.Status)
+	r.HandleFunc("/status.json", handlers.StatusJson)
+	r.HandleFunc("/anomalies", handlers.Anomalies)
+	r.HandleFunc("/board/{id:[0-9]+}/year/{year:[0-9]{4}}", handlers.Day)
+	boardRoutes(r.PathPrefix("/board/{id:[0-9]+}/year/{year:[0-9]{4}}").Subrouter())
+	r.HandleFunc("/board/{id:[0-9]+}", handlers.Day)
+	boardRoutes(r.PathPrefix("/board/{id:[0-9]+}").Subrouter())
+	r.HandleFunc("/merged/{merged:[a-z0-9-]+}/year/{year:[0-9]{4}}", handlers.Day)
+	boardRoutes(r.PathPrefix("/merged/{merged:[a-z0-9-]+}/year/{year:[0-9]{4}}").Subrouter())
+	r.HandleFunc("/merged/{merged:[a-z0-9-]+}", handlers.Day)
+	boardRoutes(r.PathPrefix("/merged/{merged:[a-z0-9-]+}").Subrouter())
+	r.HandleFunc("/year/{year:[0-9]{4}}", handlers.Day)
+	boardRoutes(r.PathPrefix("/year/{year:[0-9]{4}}").Subrouter())
+	boardRoutes(r)
 	http.Handle("/", handlers2.CombinedLoggingHandler(os.Stdout, r))
 
 	log.Printf("Listening to port %d.\n", port)
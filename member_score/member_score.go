@@ -8,34 +8,94 @@ type MemberScore struct {
 	Name string
 	Part1 int64
 	Part2 int64
+	// PersonalPart1 and PersonalPart2 are the times measured from when the
+	// member started instead of when the puzzle unlocked. Personal is set
+	// if the member registered a start for any of the days.
+	PersonalPart1 int64
+	PersonalPart2 int64
+	Personal bool
 	AocLocalScore int
 	AocGlobalScore int
+	// Points are the local points earned, recalculated the way AoC does
+	// from the times of the stars.
+	Points int
+	Stars int
+	// Count is the number of days counted. CompleteCount is the number of
+	// them with part 2 done, which Part2 is the sum of, and CompletePart1
+	// the part 1 times of those days.
 	Count int64
+	CompleteCount int64
+	CompletePart1 int64
+	// Part1Rank and Part2Rank are the ranks on a day by the time of each
+	// part, zero if the part isn't done.
+	Part1Rank int
+	Part2Rank int
+	// Rank is the rank of the totals, and RankHistory the rank of the
+	// running totals after each day, zero before the first day done.
+	// RankChange is how many places the rank went up since the day
+	// before.
+	Rank int
+	RankHistory []int
+	RankChange int
+	// Streaks are only set for totals.
+	Streaks Streaks
+	// Rating is the rating from the finishing order of each day, and
+	// RatingDeviation how uncertain it is. RatingHistory is the rating
+	// after each day, zero before the first day played, and RatingChange
+	// how much it went up since the day before. They are only set for
+	// totals.
+	Rating int
+	RatingDeviation int
+	RatingHistory []int
+	RatingChange int
+	// Part1Times and Part2Diffs are the times of each day finished, and
+	// Part1Stats and Part2DiffStats their statistics. They are only set
+	// for totals.
+	Part1Times []int64
+	Part2Diffs []int64
+	Part1Stats Stats
+	Part2DiffStats Stats
 }
 
 func (m MemberScore) Part1Avg() int64 {
 	return m.Part1 / m.Count
 }
 
+// PersonalPart1Avg is the average part 1 time from when the member
+// started.
+func (m MemberScore) PersonalPart1Avg() int64 {
+	return m.PersonalPart1 / m.Count
+}
+
 func (m MemberScore) Part2Avg() int64 {
 	if m.Part2 == 0 {
 		return 0
 	}
-	return m.Part2 / m.Count
+	return m.Part2 / m.CompleteCount
 }
 
 func (m MemberScore) Part2DiffAvg() int64 {
 	if m.Part2Diff() < 1 {
 		return 0
 	}
-	return m.Part2Diff() / m.Count
+	return m.Part2Diff() / m.CompleteCount
+}
+
+// RankDown is how many places the rank went down since the day before.
+func (m MemberScore) RankDown() int {
+	return -m.RankChange
+}
+
+// RatingDown is how much the rating went down since the day before.
+func (m MemberScore) RatingDown() int {
+	return -m.RatingChange
 }
 
 func (m MemberScore) Part2Diff() int64 {
 	if m.Part2 == 0 {
 		return -1
 	}
-	return m.Part2 - m.Part1
+	return m.Part2 - m.CompletePart1
 }
 
 type ByName []*MemberScore
@@ -99,3 +159,84 @@ func (a ByPart2Diff) Less(i, j int) bool {
 }
 func (a ByPart2Diff) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
 
+type ByPoints []*MemberScore
+func (a ByPoints) Len() int { return len(a) }
+func (a ByPoints) Less(i, j int) bool {
+	if a[i].Points == a[j].Points {
+		return a[i].Part1 < a[j].Part1
+	}
+
+	return a[i].Points > a[j].Points
+}
+func (a ByPoints) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
+
+type ByTime []*MemberScore
+func (a ByTime) Len() int { return len(a) }
+func (a ByTime) Less(i, j int) bool {
+	if a[i].Count != a[j].Count {
+		return a[i].Count > a[j].Count
+	}
+
+	// Part2 is only the sum of the days with part 2 done.
+	if a[i].CompleteCount != a[j].CompleteCount {
+		return a[i].CompleteCount > a[j].CompleteCount
+	}
+
+	if a[i].Part2 == 0 && a[j].Part2 > 0 {
+		return false
+	}
+	if a[j].Part2 == 0 && a[i].Part2 > 0 {
+		return true
+	}
+
+	if a[i].Part2 == a[j].Part2 {
+		return a[i].Part1 < a[j].Part1
+	}
+
+	return a[i].Part2 < a[j].Part2
+}
+func (a ByTime) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
+
+type ByStars []*MemberScore
+func (a ByStars) Len() int { return len(a) }
+func (a ByStars) Less(i, j int) bool {
+	if a[i].Stars != a[j].Stars {
+		return a[i].Stars > a[j].Stars
+	}
+
+	return a[i].Part2 < a[j].Part2
+}
+func (a ByStars) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
+
+type ByPersonal []*MemberScore
+func (a ByPersonal) Len() int { return len(a) }
+func (a ByPersonal) Less(i, j int) bool {
+	if a[i].Count != a[j].Count {
+		return a[i].Count > a[j].Count
+	}
+
+	if a[i].PersonalPart2 == 0 && a[j].PersonalPart2 > 0 {
+		return false
+	}
+	if a[j].PersonalPart2 == 0 && a[i].PersonalPart2 > 0 {
+		return true
+	}
+
+	if a[i].PersonalPart2 == a[j].PersonalPart2 {
+		return a[i].PersonalPart1 < a[j].PersonalPart1
+	}
+
+	return a[i].PersonalPart2 < a[j].PersonalPart2
+}
+func (a ByPersonal) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
+
+type ByRating []*MemberScore
+func (a ByRating) Len() int { return len(a) }
+func (a ByRating) Less(i, j int) bool {
+	if a[i].Rating == a[j].Rating {
+		return a[i].RatingDeviation < a[j].RatingDeviation
+	}
+
+	return a[i].Rating > a[j].Rating
+}
+func (a ByRating) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
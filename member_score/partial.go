@@ -0,0 +1,67 @@
+package member_score
+
+// The kinds of PartialPolicy.
+const (
+	// PartialComplete only counts the days with both parts done.
+	PartialComplete = "complete"
+	// PartialStars counts every day with a star. Part 2 times and averages
+	// are taken over the days with part 2 done.
+	PartialStars = "stars"
+	// PartialPenalty counts every day with a star, as if a missing part 2
+	// was done Penalty seconds after part 1.
+	PartialPenalty = "penalty"
+)
+
+// A PartialPolicy decides how days with only part 1 done count in the
+// totals.
+type PartialPolicy struct {
+	Kind string
+	Penalty int64
+}
+
+// DefaultPartialPolicy only counts the days with both parts done.
+var DefaultPartialPolicy = PartialPolicy{Kind: PartialComplete}
+
+// ValidPartialPolicy reports whether kind names a PartialPolicy.
+func ValidPartialPolicy(kind string) bool {
+	return kind == PartialComplete || kind == PartialStars || kind == PartialPenalty
+}
+
+// Add adds the times of the day score day to the totals m as policy says,
+// and reports whether the day counted.
+func (m *MemberScore) Add(day *MemberScore, policy PartialPolicy) bool {
+	part1, part2 := day.Part1, day.Part2
+	personal1, personal2 := day.PersonalPart1, day.PersonalPart2
+	if part1 == 0 {
+		return false
+	}
+
+	if part2 == 0 {
+		switch policy.Kind {
+		case PartialStars:
+			m.Count++
+			m.Part1 += part1
+			m.PersonalPart1 += personal1
+			m.Personal = m.Personal || day.Personal
+			m.Part1Times = append(m.Part1Times, part1)
+			return true
+		case PartialPenalty:
+			part2 = part1 + policy.Penalty
+			personal2 = personal1 + policy.Penalty
+		default:
+			return false
+		}
+	}
+
+	m.Count++
+	m.Part1 += part1
+	m.Part2 += part2
+	m.PersonalPart1 += personal1
+	m.PersonalPart2 += personal2
+	m.Personal = m.Personal || day.Personal
+	m.CompleteCount++
+	m.CompletePart1 += part1
+	m.Part1Times = append(m.Part1Times, part1)
+	m.Part2Diffs = append(m.Part2Diffs, part2-part1)
+	return true
+}
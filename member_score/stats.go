@@ -0,0 +1,178 @@
+package member_score
+
+import (
+	"math"
+	"sort"
+	"strings"
+)
+
+// The statistics of the part times of the totals, by the names used in
+// URLs.
+const (
+	StatAverage = "avg"
+	StatMedian = "median"
+	StatBest = "best"
+	StatWorst = "worst"
+	StatStdDev = "stddev"
+)
+
+// StatNames are the statistics in the order they are offered.
+var StatNames = []string{StatAverage, StatMedian, StatBest, StatWorst, StatStdDev}
+
+var statLabels = map[string]string{
+	StatAverage: "average",
+	StatMedian: "median",
+	StatBest: "best",
+	StatWorst: "worst",
+	StatStdDev: "standard deviation of",
+}
+
+// Stats summarizes the times of a member over the days they finished.
+type Stats struct {
+	Median int64
+	Best int64
+	Worst int64
+	StdDev int64
+}
+
+// NewStats calculates the statistics of times, in seconds.
+func NewStats(times []int64) Stats {
+	if len(times) == 0 {
+		return Stats{}
+	}
+
+	sorted := append([]int64(nil), times...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	s := Stats{
+		Best: sorted[0],
+		Worst: sorted[len(sorted)-1],
+	}
+
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		s.Median = (sorted[middle-1] + sorted[middle]) / 2
+	} else {
+		s.Median = sorted[middle]
+	}
+
+	var sum float64
+	for _, t := range sorted {
+		sum += float64(t)
+	}
+	mean := sum / float64(len(sorted))
+	var squares float64
+	for _, t := range sorted {
+		squares += (float64(t) - mean) * (float64(t) - mean)
+	}
+	s.StdDev = int64(math.Round(math.Sqrt(squares / float64(len(sorted)))))
+
+	return s
+}
+
+func (s Stats) get(stat string, average int64) int64 {
+	switch stat {
+	case StatMedian:
+		return s.Median
+	case StatBest:
+		return s.Best
+	case StatWorst:
+		return s.Worst
+	case StatStdDev:
+		return s.StdDev
+	}
+	return average
+}
+
+// Part1Stat returns the statistic named stat of the part 1 times.
+func (m MemberScore) Part1Stat(stat string) int64 {
+	return m.Part1Stats.get(stat, m.Part1Avg())
+}
+
+// Part2DiffStat returns the statistic named stat of the times from part 1
+// to part 2.
+func (m MemberScore) Part2DiffStat(stat string) int64 {
+	return m.Part2DiffStats.get(stat, m.Part2DiffAvg())
+}
+
+// UpdateStats calculates Part1Stats and Part2DiffStats from the days added.
+func (m *MemberScore) UpdateStats() {
+	m.Part1Stats = NewStats(m.Part1Times)
+	m.Part2DiffStats = NewStats(m.Part2Diffs)
+}
+
+// StatOf returns the statistic sorted by the strategy named orderBy, or
+// StatAverage if it doesn't sort by one.
+func StatOf(orderBy string) string {
+	for _, stat := range StatNames[1:] {
+		if orderBy == "part1"+stat || orderBy == "part2"+stat {
+			return stat
+		}
+	}
+	return StatAverage
+}
+
+// StatOrderBy returns the names of the strategies sorting by stat of part 1
+// and of part 2.
+func StatOrderBy(stat string) (part1 string, part2 string) {
+	if stat == StatAverage {
+		return "part1", "part2diff"
+	}
+	return "part1" + stat, "part2" + stat
+}
+
+// statStrategies returns the strategies sorting by each statistic of each
+// part, fastest first.
+func statStrategies() []ScoringStrategy {
+	var strategies []ScoringStrategy
+	for _, stat := range StatNames[1:] {
+		stat := stat
+		part1, part2 := StatOrderBy(stat)
+		strategies = append(strategies,
+			strategy{
+				name: part1,
+				label: statLabels[stat] + " part 1 time",
+				columns: defaultColumns,
+				rank: func(scores []*MemberScore) {
+					sortByStat(scores, stat,
+						func(m *MemberScore) []int64 { return m.Part1Times },
+						func(m *MemberScore) int64 { return m.Part1Stat(stat) })
+				},
+			},
+			strategy{
+				name: part2,
+				label: statLabels[stat] + " time from part 1 to part 2",
+				columns: defaultColumns,
+				rank: func(scores []*MemberScore) {
+					sortByStat(scores, stat,
+						func(m *MemberScore) []int64 { return m.Part2Diffs },
+						func(m *MemberScore) int64 { return m.Part2DiffStat(stat) })
+				},
+			},
+		)
+	}
+	return strategies
+}
+
+// sortByStat sorts scores by the value of stat of the times, lowest first.
+// Members with too few times for stat to say anything, none or a single
+// one for StatStdDev, come last. Ties are broken by the days done.
+func sortByStat(scores []*MemberScore, stat string, times func(*MemberScore) []int64, value func(*MemberScore) int64) {
+	samples := 1
+	if stat == StatStdDev {
+		samples = 2
+	}
+	sort.SliceStable(scores, func(i, j int) bool {
+		iHas, jHas := len(times(scores[i])) >= samples, len(times(scores[j])) >= samples
+		if iHas != jHas {
+			return iHas
+		}
+		if iHas && value(scores[i]) != value(scores[j]) {
+			return value(scores[i]) < value(scores[j])
+		}
+		if scores[i].Count != scores[j].Count {
+			return scores[i].Count > scores[j].Count
+		}
+		return strings.ToLower(scores[i].Name) < strings.ToLower(scores[j].Name)
+	})
+}
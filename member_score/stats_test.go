@@ -0,0 +1,42 @@
+package member_score
+
+import "testing"
+
+func TestSortByStatWithoutSamples(t *testing.T) {
+	policy := PartialPolicy{Kind: PartialStars}
+	partial := &MemberScore{Id: 1, Name: "Partial"}
+	partial.Add(&MemberScore{Part1: 100}, policy)
+	partial.Add(&MemberScore{Part1: 200}, policy)
+	once := &MemberScore{Id: 2, Name: "Once"}
+	once.Add(&MemberScore{Part1: 100, Part2: 5000}, policy)
+	twice := &MemberScore{Id: 3, Name: "Twice"}
+	twice.Add(&MemberScore{Part1: 100, Part2: 9000}, policy)
+	twice.Add(&MemberScore{Part1: 100, Part2: 9000}, policy)
+
+	tests := []struct {
+		orderBy string
+		want []int
+	}{
+		{"part2median", []int{2, 3, 1}},
+		{"part2best", []int{2, 3, 1}},
+		{"part2worst", []int{2, 3, 1}},
+		{"part2stddev", []int{3, 1, 2}},
+		{"part1median", []int{3, 2, 1}},
+	}
+	for _, test := range tests {
+		scores := []*MemberScore{partial, once, twice}
+		for _, ms := range scores {
+			ms.UpdateStats()
+		}
+		strategy, ok := LookupStrategy(test.orderBy)
+		if !ok {
+			t.Fatalf("no strategy %s", test.orderBy)
+		}
+		strategy.Rank(scores)
+		for i, id := range test.want {
+			if scores[i].Id != id {
+				t.Errorf("%s ranks %d at %d, want %d", test.orderBy, scores[i].Id, i+1, id)
+			}
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package member_score
+
+import "sort"
+
+// The columns a ScoringStrategy can ask for in the totals table, besides
+// the name and the part 1 and part 2 times which are always shown.
+const (
+	ColumnGlobalScore = "ogscore"
+	ColumnLocalScore = "olscore"
+	ColumnDays = "days"
+	ColumnPoints = "points"
+	ColumnStars = "stars"
+	ColumnTime = "time"
+	ColumnPersonal = "personal"
+	ColumnStreaks = "streaks"
+	ColumnRating = "rating"
+)
+
+// A ScoringStrategy ranks the totals of a leaderboard.
+type ScoringStrategy interface {
+	// Name identifies the strategy in the configuration and as orderBy in
+	// URLs.
+	Name() string
+	// Label describes the ranking in the UI.
+	Label() string
+	// Columns are the columns of the totals table the ranking needs.
+	Columns() []string
+	// Rank sorts scores, best first.
+	Rank(scores []*MemberScore)
+}
+
+type strategy struct {
+	name string
+	label string
+	columns []string
+	rank func(scores []*MemberScore)
+}
+
+func (s strategy) Name() string { return s.name }
+func (s strategy) Label() string { return s.label }
+func (s strategy) Columns() []string { return s.columns }
+func (s strategy) Rank(scores []*MemberScore) { s.rank(scores) }
+
+var defaultColumns = []string{ColumnGlobalScore, ColumnLocalScore, ColumnDays, ColumnPoints, ColumnStreaks}
+
+var streakColumns = []string{ColumnDays, ColumnStreaks}
+
+// DefaultStrategy ranks by the number of days done, and then by the time
+// between part 1 and part 2.
+var DefaultStrategy ScoringStrategy = strategy{
+	name: "part2diff",
+	label: "days done, then time from part 1 to part 2",
+	columns: defaultColumns,
+	rank: func(scores []*MemberScore) { sort.Sort(ByPart2Diff(scores)) },
+}
+
+// Strategies are the built-in scoring strategies. The first ones rank the
+// totals the ways a leaderboard can be configured for, the others sort by a
+// single column or statistic.
+var Strategies = append([]ScoringStrategy{
+	DefaultStrategy,
+	strategy{
+		name: "time",
+		label: "days done, then total time",
+		columns: []string{ColumnDays, ColumnTime},
+		rank: func(scores []*MemberScore) { sort.Sort(ByTime(scores)) },
+	},
+	strategy{
+		name: "points",
+		label: "local points",
+		columns: []string{ColumnLocalScore, ColumnDays, ColumnPoints},
+		rank: func(scores []*MemberScore) { sort.Sort(ByPoints(scores)) },
+	},
+	strategy{
+		name: "stars",
+		label: "stars, then total time",
+		columns: []string{ColumnStars, ColumnDays, ColumnTime},
+		rank: func(scores []*MemberScore) { sort.Sort(ByStars(scores)) },
+	},
+	strategy{
+		name: "personal",
+		label: "days done, then total time from each member's own start",
+		columns: []string{ColumnDays, ColumnPersonal},
+		rank: func(scores []*MemberScore) { sort.Sort(ByPersonal(scores)) },
+	},
+	strategy{
+		name: "rating",
+		label: "rating from the finishing order of each day",
+		columns: []string{ColumnDays, ColumnRating, ColumnPoints},
+		rank: func(scores []*MemberScore) { sort.Sort(ByRating(scores)) },
+	},
+	strategy{
+		name: "part1",
+		label: "part 1 time",
+		columns: defaultColumns,
+		rank: func(scores []*MemberScore) { sort.Sort(ByPart1(scores)) },
+	},
+	strategy{
+		name: "part2",
+		label: "part 2 time",
+		columns: defaultColumns,
+		rank: func(scores []*MemberScore) { sort.Sort(ByPart2(scores)) },
+	},
+	strategy{
+		name: "ogscore",
+		label: "AoC global score",
+		columns: defaultColumns,
+		rank: func(scores []*MemberScore) { sort.Sort(ByAocGlobalScore(scores)) },
+	},
+	strategy{
+		name: "olscore",
+		label: "AoC local score",
+		columns: defaultColumns,
+		rank: func(scores []*MemberScore) { sort.Sort(ByAocLocalScore(scores)) },
+	},
+	strategy{
+		name: "name",
+		label: "name",
+		columns: defaultColumns,
+		rank: func(scores []*MemberScore) { sort.Sort(ByName(scores)) },
+	},
+	strategy{
+		name: "streak",
+		label: "current streak of days with both stars",
+		columns: streakColumns,
+		rank: func(scores []*MemberScore) { sort.Sort(ByStreak(scores)) },
+	},
+	strategy{
+		name: "longeststreak",
+		label: "longest streak of days with both stars",
+		columns: streakColumns,
+		rank: func(scores []*MemberScore) { sort.Sort(ByLongestStreak(scores)) },
+	},
+	strategy{
+		name: "faststreak",
+		label: "current streak of days with both stars within 24 hours",
+		columns: streakColumns,
+		rank: func(scores []*MemberScore) { sort.Sort(ByFastStreak(scores)) },
+	},
+	strategy{
+		name: "missed",
+		label: "fewest days missed",
+		columns: streakColumns,
+		rank: func(scores []*MemberScore) { sort.Sort(ByMissed(scores)) },
+	},
+}, statStrategies()...)
+
+// LookupStrategy returns the built-in strategy called name.
+func LookupStrategy(name string) (ScoringStrategy, bool) {
+	for _, s := range Strategies {
+		if s.Name() == name {
+			return s, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,94 @@
+package member_score
+
+import "strings"
+
+// Streaks measure how continuously a member has solved the days.
+type Streaks struct {
+	// Current and Longest are runs of consecutive days with both stars.
+	Current int
+	Longest int
+	// CurrentFast and LongestFast are runs of consecutive days with both
+	// stars within 24 hours of the unlock.
+	CurrentFast int
+	LongestFast int
+	// Missed is the number of days without both stars.
+	Missed int
+}
+
+// Next continues the streaks with the next day, given the member's score
+// of it, or nil if they have no star on it.
+func (s *Streaks) Next(day *MemberScore) {
+	if day == nil || day.Part2 == 0 {
+		s.Current = 0
+		s.CurrentFast = 0
+		s.Missed++
+		return
+	}
+
+	s.Current++
+	if day.Part2 < 24*60*60 {
+		s.CurrentFast++
+	} else {
+		s.CurrentFast = 0
+	}
+
+	if s.Current > s.Longest {
+		s.Longest = s.Current
+	}
+	if s.CurrentFast > s.LongestFast {
+		s.LongestFast = s.CurrentFast
+	}
+}
+
+type ByStreak []*MemberScore
+func (a ByStreak) Len() int { return len(a) }
+func (a ByStreak) Less(i, j int) bool {
+	if a[i].Streaks.Current != a[j].Streaks.Current {
+		return a[i].Streaks.Current > a[j].Streaks.Current
+	}
+	if a[i].Streaks.Longest != a[j].Streaks.Longest {
+		return a[i].Streaks.Longest > a[j].Streaks.Longest
+	}
+
+	return strings.ToLower(a[i].Name) < strings.ToLower(a[j].Name)
+}
+func (a ByStreak) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
+
+type ByLongestStreak []*MemberScore
+func (a ByLongestStreak) Len() int { return len(a) }
+func (a ByLongestStreak) Less(i, j int) bool {
+	if a[i].Streaks.Longest != a[j].Streaks.Longest {
+		return a[i].Streaks.Longest > a[j].Streaks.Longest
+	}
+	if a[i].Streaks.Current != a[j].Streaks.Current {
+		return a[i].Streaks.Current > a[j].Streaks.Current
+	}
+
+	return strings.ToLower(a[i].Name) < strings.ToLower(a[j].Name)
+}
+func (a ByLongestStreak) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
+
+type ByFastStreak []*MemberScore
+func (a ByFastStreak) Len() int { return len(a) }
+func (a ByFastStreak) Less(i, j int) bool {
+	if a[i].Streaks.CurrentFast != a[j].Streaks.CurrentFast {
+		return a[i].Streaks.CurrentFast > a[j].Streaks.CurrentFast
+	}
+	if a[i].Streaks.LongestFast != a[j].Streaks.LongestFast {
+		return a[i].Streaks.LongestFast > a[j].Streaks.LongestFast
+	}
+
+	return strings.ToLower(a[i].Name) < strings.ToLower(a[j].Name)
+}
+func (a ByFastStreak) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
+
+type ByMissed []*MemberScore
+func (a ByMissed) Len() int { return len(a) }
+func (a ByMissed) Less(i, j int) bool {
+	if a[i].Streaks.Missed != a[j].Streaks.Missed {
+		return a[i].Streaks.Missed < a[j].Streaks.Missed
+	}
+
+	return strings.ToLower(a[i].Name) < strings.ToLower(a[j].Name)
+}
+func (a ByMissed) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
@@ -0,0 +1,125 @@
+package member_score
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// The kinds of TeamAggregation.
+const (
+	// TeamBest scores a team by the sum of the points of its Best members.
+	TeamBest = "best"
+	// TeamAverage scores a team by the average points of its members.
+	TeamAverage = "average"
+	// TeamStars scores a team by the stars of all its members.
+	TeamStars = "stars"
+)
+
+// A Team is a group of members competing together, by member id.
+type Team struct {
+	Name string
+	Members []int
+}
+
+// A TeamAggregation decides how the scores of the members of a team make
+// up its score.
+type TeamAggregation struct {
+	Kind string
+	Best int
+}
+
+// ValidTeamAggregation reports whether kind names a TeamAggregation.
+func ValidTeamAggregation(kind string) bool {
+	return kind == TeamBest || kind == TeamAverage || kind == TeamStars
+}
+
+// Label describes the aggregation in the UI.
+func (a TeamAggregation) Label() string {
+	switch a.Kind {
+	case TeamAverage:
+		return "average points of the members"
+	case TeamStars:
+		return "stars of all the members"
+	}
+	if a.Best == 1 {
+		return "points of the best member"
+	}
+	return "points of the best " + strconv.Itoa(a.Best) + " members"
+}
+
+// A TeamScore is the standing of a team. Members are the scores of its
+// members, best first, Present how many of its members are on the
+// leaderboard, and Counted how many of them make up Score.
+type TeamScore struct {
+	Name string
+	Rank int
+	Score float64
+	Members []*MemberScore
+	Present int
+	Counted int
+}
+
+// TeamStandings scores the teams with at least one member on the
+// leaderboard, the members in present, by aggregation of their scores,
+// best first. Members without scores count as having nothing done.
+func TeamStandings(teams []Team, present map[int]bool, scores map[int]*MemberScore, aggregation TeamAggregation) []*TeamScore {
+	var standings []*TeamScore
+	for _, team := range teams {
+		ts := &TeamScore{Name: team.Name}
+		for _, id := range team.Members {
+			if !present[id] {
+				continue
+			}
+			ts.Present++
+			if score, ok := scores[id]; ok {
+				ts.Members = append(ts.Members, score)
+			}
+		}
+		if ts.Present == 0 {
+			continue
+		}
+		sort.Sort(ByPoints(ts.Members))
+
+		switch aggregation.Kind {
+		case TeamAverage:
+			for _, ms := range ts.Members {
+				ts.Score += float64(ms.Points)
+			}
+			ts.Score = math.Round(ts.Score/float64(ts.Present)*10) / 10
+			ts.Counted = ts.Present
+		case TeamStars:
+			sort.Sort(ByStars(ts.Members))
+			for _, ms := range ts.Members {
+				ts.Score += float64(ms.Stars)
+			}
+			ts.Counted = len(ts.Members)
+		default:
+			for i, ms := range ts.Members {
+				if i == aggregation.Best {
+					break
+				}
+				ts.Score += float64(ms.Points)
+				ts.Counted++
+			}
+		}
+
+		standings = append(standings, ts)
+	}
+
+	sort.SliceStable(standings, func(i, j int) bool {
+		if standings[i].Score == standings[j].Score {
+			return strings.ToLower(standings[i].Name) < strings.ToLower(standings[j].Name)
+		}
+		return standings[i].Score > standings[j].Score
+	})
+	for i, ts := range standings {
+		ts.Rank = i + 1
+		if i > 0 && standings[i-1].Score == ts.Score {
+			ts.Rank = standings[i-1].Rank
+		}
+	}
+
+	return standings
+}
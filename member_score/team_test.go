@@ -0,0 +1,90 @@
+package member_score
+
+import "testing"
+
+func TestTeamStandings(t *testing.T) {
+	scores := map[int]*MemberScore{
+		1: {Id: 1, Name: "Ann", Points: 100, Stars: 10},
+		2: {Id: 2, Name: "Bob", Points: 60, Stars: 8},
+		3: {Id: 3, Name: "Cid", Points: 30, Stars: 6},
+		4: {Id: 4, Name: "Dan", Points: 90, Stars: 9},
+		5: {Id: 5, Name: "Eve", Points: 50, Stars: 2},
+	}
+	present := map[int]bool{1: true, 2: true, 3: true, 4: true, 5: true, 6: true}
+	teams := []Team{
+		{Name: "Red", Members: []int{1, 2, 3}},
+		{Name: "blue", Members: []int{4, 5, 6}},
+		{Name: "Gone", Members: []int{7, 8}},
+	}
+
+	type standing struct {
+		name string
+		rank int
+		score float64
+		counted int
+	}
+	tests := []struct {
+		aggregation TeamAggregation
+		want []standing
+	}{
+		{
+			aggregation: TeamAggregation{Kind: TeamBest, Best: 2},
+			want: []standing{{"Red", 1, 160, 2}, {"blue", 2, 140, 2}},
+		},
+		{
+			aggregation: TeamAggregation{Kind: TeamBest, Best: 1},
+			want: []standing{{"Red", 1, 100, 1}, {"blue", 2, 90, 1}},
+		},
+		{
+			// Member 6 is on the leaderboard without any score.
+			aggregation: TeamAggregation{Kind: TeamAverage},
+			want: []standing{{"Red", 1, 63.3, 3}, {"blue", 2, 46.7, 3}},
+		},
+		{
+			aggregation: TeamAggregation{Kind: TeamStars},
+			want: []standing{{"Red", 1, 24, 3}, {"blue", 2, 11, 2}},
+		},
+	}
+
+	for _, test := range tests {
+		standings := TeamStandings(teams, present, scores, test.aggregation)
+		if len(standings) != len(test.want) {
+			t.Errorf("%s: got %d teams, want %d", test.aggregation.Label(), len(standings), len(test.want))
+			continue
+		}
+		for i, ts := range standings {
+			got := standing{ts.Name, ts.Rank, ts.Score, ts.Counted}
+			if got != test.want[i] {
+				t.Errorf("%s: got %+v, want %+v", test.aggregation.Label(), got, test.want[i])
+			}
+			if ts.Present != 3 {
+				t.Errorf("%s: %s has %d members present, want 3", test.aggregation.Label(), ts.Name, ts.Present)
+			}
+		}
+	}
+}
+
+func TestTeamStandingsTies(t *testing.T) {
+	scores := map[int]*MemberScore{
+		1: {Id: 1, Points: 50},
+		2: {Id: 2, Points: 50},
+		3: {Id: 3, Points: 10},
+	}
+	present := map[int]bool{1: true, 2: true, 3: true}
+	teams := []Team{
+		{Name: "beta", Members: []int{2}},
+		{Name: "Alpha", Members: []int{1}},
+		{Name: "Gamma", Members: []int{3}},
+	}
+
+	standings := TeamStandings(teams, present, scores, TeamAggregation{Kind: TeamBest, Best: 3})
+	want := []struct {
+		name string
+		rank int
+	}{{"Alpha", 1}, {"beta", 1}, {"Gamma", 3}}
+	for i, ts := range standings {
+		if ts.Name != want[i].name || ts.Rank != want[i].rank {
+			t.Errorf("got %s ranked %d at %d, want %s ranked %d", ts.Name, ts.Rank, i+1, want[i].name, want[i].rank)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package mock_aoc
+
+import (
+	"crypto/sha1"
+	"encoding/json"
+	"fmt"
+	"github.com/gorilla/mux"
+	"github.com/tlj/aoc-leaderboard-go/leaderboard"
+	"log"
+	"math/rand"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+)
+
+// A Server imitates the private leaderboard API of adventofcode.com, so the
+// leaderboard can be developed and tested without AoC.
+type Server struct {
+	// SessionCookie is the only session cookie accepted. Any cookie is
+	// accepted if it is empty.
+	SessionCookie string
+	// Store, if set, serves the newest payload stored for each leaderboard.
+	Store *leaderboard.Store
+	// Source serves the leaderboards not found in Store.
+	Source leaderboard.Source
+	// Speed makes the members earn their stars over time: the stars served
+	// are the ones earned before the event started plus Speed times the
+	// time since Start. All stars are served if it is zero.
+	Speed float64
+	Start time.Time
+	// ErrorRate, RedirectRate and SlowRate are the fractions of requests
+	// answered with a 5xx error, a redirect to the login page, or after
+	// waiting Delay.
+	ErrorRate float64
+	RedirectRate float64
+	SlowRate float64
+	Delay time.Duration
+}
+
+// Handler returns the routes of the mock API.
+func (s *Server) Handler() http.Handler {
+	r := mux.NewRouter()
+	r.HandleFunc("/{year:[0-9]{4}}/leaderboard/private/view/{id:[0-9]+}.json", s.leaderboard)
+	return r
+}
+
+func (s *Server) leaderboard(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	year, _ := strconv.ParseInt(vars["year"], 10, 64)
+	id, _ := strconv.ParseInt(vars["id"], 10, 64)
+	login := fmt.Sprintf("/%d/leaderboard/private", year)
+
+	if s.SlowRate > 0 && rand.Float64() < s.SlowRate {
+		time.Sleep(s.Delay)
+	}
+	if s.ErrorRate > 0 && rand.Float64() < s.ErrorRate {
+		http.Error(w, "Simulated error", http.StatusBadGateway)
+		return
+	}
+
+	cookie, err := r.Cookie("session")
+	if err != nil || (s.SessionCookie != "" && cookie.Value != s.SessionCookie) ||
+		(s.RedirectRate > 0 && rand.Float64() < s.RedirectRate) {
+		http.Redirect(w, r, login, http.StatusFound)
+		return
+	}
+
+	body, err := s.payload(year, id)
+	if os.IsNotExist(err) {
+		http.Redirect(w, r, login, http.StatusFound)
+		return
+	}
+	if err != nil {
+		log.Printf("Error serving leaderboard %d year %d: %v", id, year, err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	etag := fmt.Sprintf(`"%x"`, sha1.Sum(body))
+	w.Header().Set("ETag", etag)
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(body)
+}
+
+// payload returns the leaderboard to serve, with only the stars earned so
+// far if Speed is set.
+func (s *Server) payload(year int64, id int64) ([]byte, error) {
+	var body []byte
+	var err error
+	if s.Store != nil {
+		body, _, err = s.Store.Latest(year, id)
+	}
+	if s.Store == nil || os.IsNotExist(err) {
+		body, err = s.Source.Fetch(year, id)
+	}
+	if err != nil || s.Speed == 0 {
+		return body, err
+	}
+
+	event, err := leaderboard.DecodeEvent(body)
+	if err != nil {
+		return nil, fmt.Errorf("decoding leaderboard: %v", err)
+	}
+
+	eventStartsAt := time.Unix(leaderboard.Day{Year: year, Day: 1}.DayStartsAt(), 0)
+	elapsed := time.Duration(float64(time.Since(s.Start)) * s.Speed)
+	return json.Marshal(event.Before(eventStartsAt.Add(elapsed)))
+}
@@ -0,0 +1,113 @@
+// Package rating rates players from the results of matches with any
+// number of players, the way Glicko rates them from games of two. Each
+// match is one rating period, in which every player played everyone else
+// in the match.
+package rating
+
+import "math"
+
+const (
+	// Initial is the rating of a player before their first match.
+	Initial = 1500.0
+	// InitialDeviation is how uncertain the rating of a new player is.
+	InitialDeviation = 350.0
+	// MinDeviation keeps the ratings of regular players from settling so
+	// much that they stop moving.
+	MinDeviation = 50.0
+)
+
+var q = math.Ln10 / 400
+
+// A Rating is the estimated strength of a player, and the deviation, how
+// uncertain the estimate is. The true strength is likely within two
+// deviations of Value.
+type Rating struct {
+	Value float64
+	Deviation float64
+}
+
+// A Result is how a player did in a match. Place is 1 for the winner, and
+// players with the same Place tied. Place 0 means the player was absent:
+// they lose to everyone who played, and aren't compared to others who were
+// absent.
+type Result struct {
+	Id int
+	Place int
+}
+
+// An Engine keeps the ratings of the players over a series of matches.
+type Engine struct {
+	ratings map[int]Rating
+}
+
+// NewEngine returns an engine without any ratings.
+func NewEngine() *Engine {
+	return &Engine{ratings: make(map[int]Rating)}
+}
+
+// Rating returns the rating of player id, and whether they have played.
+func (e *Engine) Rating(id int) (Rating, bool) {
+	r, ok := e.ratings[id]
+	return r, ok
+}
+
+// Play updates the ratings with the results of one match.
+func (e *Engine) Play(results []Result) {
+	before := make(map[int]Rating)
+	for _, result := range results {
+		r, ok := e.ratings[result.Id]
+		if !ok {
+			r = Rating{Value: Initial, Deviation: InitialDeviation}
+		}
+		before[result.Id] = r
+	}
+
+	for _, player := range results {
+		r := before[player.Id]
+
+		var variance, improvement float64
+		for _, opponent := range results {
+			if opponent.Id == player.Id || (player.Place == 0 && opponent.Place == 0) {
+				continue
+			}
+			o := before[opponent.Id]
+			g := g(o.Deviation)
+			expected := expected(r.Value, o.Value, g)
+			variance += g * g * expected * (1 - expected)
+			improvement += g * (score(player.Place, opponent.Place) - expected)
+		}
+		if variance == 0 {
+			e.ratings[player.Id] = r
+			continue
+		}
+
+		d2 := 1 / (q * q * variance)
+		precision := 1/(r.Deviation*r.Deviation) + 1/d2
+		e.ratings[player.Id] = Rating{
+			Value: r.Value + q/precision*improvement,
+			Deviation: math.Max(math.Sqrt(1/precision), MinDeviation),
+		}
+	}
+}
+
+func g(deviation float64) float64 {
+	return 1 / math.Sqrt(1+3*q*q*deviation*deviation/(math.Pi*math.Pi))
+}
+
+func expected(rating float64, opponent float64, g float64) float64 {
+	return 1 / (1 + math.Pow(10, -g*(rating-opponent)/400))
+}
+
+// score is the outcome of a game between players in place and opponent in
+// against: 1 for a win, 0.5 for a tie and 0 for a loss.
+func score(place int, against int) float64 {
+	switch {
+	case place == against:
+		return 0.5
+	case place == 0:
+		return 0
+	case against == 0 || place < against:
+		return 1
+	}
+	return 0
+}
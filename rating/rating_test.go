@@ -0,0 +1,100 @@
+package rating
+
+import (
+	"math"
+	"testing"
+)
+
+// TestPlayGlickmanExample plays the example of Glickman's paper on the
+// Glicko system: a 1500 player with deviation 200 beats a 1400 player and
+// loses to a 1550 and a 1700 player.
+func TestPlayGlickmanExample(t *testing.T) {
+	e := NewEngine()
+	e.ratings[1] = Rating{Value: 1500, Deviation: 200}
+	e.ratings[2] = Rating{Value: 1400, Deviation: 30}
+	e.ratings[3] = Rating{Value: 1550, Deviation: 100}
+	e.ratings[4] = Rating{Value: 1700, Deviation: 300}
+
+	e.Play([]Result{{Id: 3, Place: 1}, {Id: 4, Place: 1}, {Id: 1, Place: 2}, {Id: 2, Place: 3}})
+
+	r, _ := e.Rating(1)
+	if math.Abs(r.Value-1464.1) > 0.5 || math.Abs(r.Deviation-151.4) > 0.5 {
+		t.Errorf("got %.1f with deviation %.1f, want 1464.1 with deviation 151.4", r.Value, r.Deviation)
+	}
+}
+
+func TestPlay(t *testing.T) {
+	tests := []struct {
+		name string
+		results []Result
+		// order lists the players from the highest rating to the lowest,
+		// and equal the pairs that should end up rated the same.
+		order []int
+		equal [][2]int
+	}{
+		{
+			name: "finishing order",
+			results: []Result{{Id: 1, Place: 1}, {Id: 2, Place: 2}, {Id: 3, Place: 3}},
+			order: []int{1, 2, 3},
+		},
+		{
+			name: "tie",
+			results: []Result{{Id: 1, Place: 1}, {Id: 2, Place: 1}, {Id: 3, Place: 3}},
+			order: []int{1, 3},
+			equal: [][2]int{{1, 2}},
+		},
+		{
+			name: "absent players lose to everyone but each other",
+			results: []Result{{Id: 1, Place: 1}, {Id: 2, Place: 2}, {Id: 3}, {Id: 4}},
+			order: []int{1, 2, 3},
+			equal: [][2]int{{3, 4}},
+		},
+	}
+
+	for _, test := range tests {
+		e := NewEngine()
+		e.Play(test.results)
+		for i := 1; i < len(test.order); i++ {
+			higher, _ := e.Rating(test.order[i-1])
+			lower, _ := e.Rating(test.order[i])
+			if higher.Value <= lower.Value {
+				t.Errorf("%s: player %d is rated %.1f, not above player %d at %.1f", test.name,
+					test.order[i-1], higher.Value, test.order[i], lower.Value)
+			}
+		}
+		for _, pair := range test.equal {
+			a, _ := e.Rating(pair[0])
+			b, _ := e.Rating(pair[1])
+			if math.Abs(a.Value-b.Value) > 1e-9 {
+				t.Errorf("%s: players %d and %d are rated %.1f and %.1f", test.name, pair[0], pair[1], a.Value, b.Value)
+			}
+		}
+
+		for _, result := range test.results {
+			r, ok := e.Rating(result.Id)
+			if !ok {
+				t.Errorf("%s: player %d has no rating", test.name, result.Id)
+			}
+			if r.Deviation >= InitialDeviation {
+				t.Errorf("%s: deviation of player %d is still %.1f", test.name, result.Id, r.Deviation)
+			}
+		}
+	}
+}
+
+func TestPlayMinDeviation(t *testing.T) {
+	e := NewEngine()
+	for i := 0; i < 200; i++ {
+		e.Play([]Result{{Id: 1, Place: 1}, {Id: 2, Place: 2}})
+	}
+	r, _ := e.Rating(1)
+	if r.Deviation < MinDeviation {
+		t.Errorf("deviation %.1f is below %.1f", r.Deviation, MinDeviation)
+	}
+}
+
+func TestRatingBeforePlaying(t *testing.T) {
+	if _, ok := NewEngine().Rating(1); ok {
+		t.Error("player who hasn't played has a rating")
+	}
+}